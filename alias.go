@@ -0,0 +1,243 @@
+package gohetznerdns
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Resolves the target of an ALIAS record to its addresses and the TTL of the answer.
+type AliasResolver interface {
+	LookupAlias(ctx context.Context, host string) ([]net.IP, int, error)
+}
+
+// Address lookup as implemented by [net.Resolver].
+type IPAddrResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Adapts an [IPAddrResolver] to an [AliasResolver]. Since such resolvers do not expose the
+// TTL of the answer, TTL is reported for every lookup.
+type IPAddrAliasResolver struct {
+	Resolver IPAddrResolver
+	TTL      int
+}
+
+func (resolver *IPAddrAliasResolver) LookupAlias(ctx context.Context, host string) ([]net.IP, int, error) {
+	addresses, err := resolver.Resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, 0, err
+	}
+	ips := make([]net.IP, 0, len(addresses))
+	for _, address := range addresses {
+		ips = append(ips, address.IP)
+	}
+	return ips, resolver.TTL, nil
+}
+
+// Queries A and AAAA records from a recursive DNS server over UDP and reports the lowest TTL
+// of the answers, including the CNAME records leading to them.
+type DNSAliasResolver struct {
+	// Server address, e.g. "9.9.9.9:53".
+	Server string
+}
+
+func (resolver *DNSAliasResolver) LookupAlias(ctx context.Context, host string) ([]net.IP, int, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(host, ".") + ".")
+	if err != nil {
+		return nil, 0, err
+	}
+	var ips []net.IP
+	ttl := -1
+	for _, queryType := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		answers, err := resolver.query(ctx, name, queryType)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, answer := range answers {
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				ips = append(ips, net.IP(body.A[:]))
+			case *dnsmessage.AAAAResource:
+				ips = append(ips, net.IP(body.AAAA[:]))
+			case *dnsmessage.CNAMEResource:
+			default:
+				continue
+			}
+			if ttl < 0 || int(answer.Header.TTL) < ttl {
+				ttl = int(answer.Header.TTL)
+			}
+		}
+	}
+	return ips, max(ttl, 0), nil
+}
+
+func (resolver *DNSAliasResolver) query(ctx context.Context, name dnsmessage.Name, queryType dnsmessage.Type) ([]dnsmessage.Resource, error) {
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+	query, err := (&dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: queryType, Class: dnsmessage.ClassINET}},
+	}).Pack()
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	connection, err := dialer.DialContext(ctx, "udp", resolver.Server)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		connection.SetDeadline(deadline)
+	} else {
+		connection.SetDeadline(time.Now().Add(5 * time.Second))
+	}
+	if _, err := connection.Write(query); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, 4096)
+	for {
+		n, err := connection.Read(buffer)
+		if err != nil {
+			return nil, err
+		}
+		response := new(dnsmessage.Message)
+		if err := response.Unpack(buffer[:n]); err != nil || response.Header.ID != id || !response.Header.Response {
+			// Ignore stray datagrams and wait for the answer to the query.
+			continue
+		}
+		switch {
+		case response.Header.Truncated:
+			return nil, fmt.Errorf("alias: truncated answer for %s %s", name, queryType)
+		case response.Header.RCode == dnsmessage.RCodeNameError:
+			return nil, nil
+		case response.Header.RCode != dnsmessage.RCodeSuccess:
+			return nil, fmt.Errorf("alias: %s %s answered %s", name, queryType, response.Header.RCode)
+		}
+		return response.Answers, nil
+	}
+}
+
+// Outcome of an [AliasFlattener.Sync].
+type AliasSyncResult struct {
+	Addresses []string
+	TTL       int
+	Changes   []*RecordChange
+	Err       error
+}
+
+// Emulates an ALIAS record by keeping the A and AAAA records of a name equal to the resolved
+// addresses of a target host. Addresses that stay resolvable keep their records, which are only
+// updated when their TTL lies outside [MinTTL, resolved TTL].
+type AliasFlattener struct {
+	dns      HetznerDNS
+	resolver AliasResolver
+	zoneId   *string
+	name     *string
+	target   string
+
+	// Lower bound of the TTL of created records and of the time between syncs, defaults to 60.
+	MinTTL int
+	// Upper bound of the TTL of created records and of the time between syncs, defaults to 3600.
+	MaxTTL int
+}
+
+// Creates a flattener writing the addresses of target to the records of name, e.g. "@".
+func NewAliasFlattener(dns HetznerDNS, resolver AliasResolver, zoneId, name *string, target string) *AliasFlattener {
+	return &AliasFlattener{dns: dns.WithoutCache(), resolver: resolver, zoneId: zoneId, name: name, target: target, MinTTL: 60, MaxTTL: 3600}
+}
+
+// Resolves the target and brings the records in line with its addresses. Created records and
+// records with a TTL outside [MinTTL, resolved TTL] get the resolved TTL, clamped to
+// [MinTTL, MaxTTL]. Nothing is deleted when the target resolves to no address at all.
+func (flattener *AliasFlattener) Sync(ctx context.Context) *AliasSyncResult {
+	result := &AliasSyncResult{TTL: flattener.MinTTL}
+	ips, ttl, err := flattener.resolver.LookupAlias(ctx, flattener.target)
+	if err != nil {
+		result.Err = fmt.Errorf("alias: resolving %s: %w", flattener.target, err)
+		return result
+	}
+	if len(ips) == 0 {
+		result.Err = fmt.Errorf("alias: %s has no addresses", flattener.target)
+		return result
+	}
+	result.TTL = min(max(ttl, flattener.MinTTL), flattener.MaxTTL)
+	desired := map[string][]string{}
+	for _, ip := range ips {
+		recordType := iif(ip.To4() != nil, "A", "AAAA")
+		if !containsFold(desired[recordType], ip.String()) {
+			desired[recordType] = append(desired[recordType], ip.String())
+			result.Addresses = append(result.Addresses, ip.String())
+		}
+	}
+	sort.Strings(result.Addresses)
+
+	records := flattener.dns.GetRecordService()
+	var creates, updates, deletes []*RecordChange
+	for _, recordType := range []string{"A", "AAAA"} {
+		rrset, err := records.GetRRSet(flattener.zoneId, flattener.name, &recordType)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		current := map[string]bool{}
+		for _, record := range rrset.Records {
+			ip := net.ParseIP(deref(record.Value))
+			value := iif(ip != nil, ip.String(), deref(record.Value))
+			switch {
+			case current[value] || !containsFold(desired[recordType], value):
+				deletes = append(deletes, &RecordChange{Action: ChangeDelete, Before: record})
+			case record.TTL == nil || *record.TTL > result.TTL || *record.TTL < flattener.MinTTL:
+				update := copyRecord(record, true)
+				update.TTL = ptr(result.TTL)
+				updates = append(updates, &RecordChange{Action: ChangeUpdate, Before: record, After: update})
+			}
+			current[value] = true
+		}
+		for _, value := range desired[recordType] {
+			if !current[value] {
+				creates = append(creates, &RecordChange{Action: ChangeCreate, After: &Record{
+					ZoneId: flattener.zoneId, Name: flattener.name, Type: ptr(recordType), Value: ptr(value), TTL: ptr(result.TTL),
+				}})
+			}
+		}
+	}
+	result.Changes, result.Err = applyRecordChanges(records, append(append(creates, updates...), deletes...))
+	return result
+}
+
+// Syncs until the context is done, waiting the resolved TTL between syncs, and sends every
+// result on the returned channel, which is closed when the flattener stops.
+func (flattener *AliasFlattener) Run(ctx context.Context) <-chan *AliasSyncResult {
+	results := make(chan *AliasSyncResult)
+	go func() {
+		defer close(results)
+		for {
+			result := flattener.Sync(ctx)
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+			timer := time.NewTimer(time.Duration(result.TTL) * time.Second)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	return results
+}
@@ -0,0 +1,154 @@
+package gohetznerdns
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"gotest.tools/assert"
+)
+
+type stubAliasResolver struct {
+	mu  sync.Mutex
+	ips []string
+	ttl int
+}
+
+func (resolver *stubAliasResolver) LookupAlias(ctx context.Context, host string) ([]net.IP, int, error) {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+	var ips []net.IP
+	for _, ip := range resolver.ips {
+		ips = append(ips, net.ParseIP(ip))
+	}
+	return ips, resolver.ttl, nil
+}
+
+func TestAliasFlattenerSync(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	kept := api.addRecord(*zone.Id, "@", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "@", "A", "192.0.2.9", nil)
+	api.addRecord(*zone.Id, "@", "MX", "10 mail", nil)
+	resolver := &stubAliasResolver{ips: []string{"192.0.2.1", "192.0.2.2", "2001:db8::1"}, ttl: 30}
+	flattener := NewAliasFlattener(api.dns(t), resolver, zone.Id, ptr("@"), "cdn.example.net")
+
+	result := flattener.Sync(context.Background())
+	assert.NilError(t, result.Err)
+	assert.Equal(t, result.TTL, 60)
+	assert.DeepEqual(t, result.Addresses, []string{"192.0.2.1", "192.0.2.2", "2001:db8::1"})
+	assert.Equal(t, len(result.Changes), 4)
+	records := api.zoneRecords(*zone.Id)
+	assert.DeepEqual(t, recordValues(records, "@", "A"), []string{"192.0.2.1", "192.0.2.2"})
+	assert.DeepEqual(t, recordValues(records, "@", "AAAA"), []string{"2001:db8::1"})
+	assert.DeepEqual(t, recordValues(records, "@", "MX"), []string{"10 mail"})
+	for _, record := range records {
+		if *record.Type != "MX" {
+			assert.Equal(t, *record.TTL, 60)
+		}
+		if *record.Value == "192.0.2.1" {
+			assert.Equal(t, *record.Id, *kept.Id)
+		}
+	}
+
+	result = flattener.Sync(context.Background())
+	assert.NilError(t, result.Err)
+	assert.Equal(t, len(result.Changes), 0)
+
+	// A longer resolved TTL leaves the records alone, a shorter one lowers them.
+	resolver.ttl = 600
+	result = flattener.Sync(context.Background())
+	assert.Equal(t, len(result.Changes), 0)
+	api.addRecord(*zone.Id, "@", "A", "192.0.2.3", ptr(7200))
+	resolver.ips = append(resolver.ips, "192.0.2.3")
+	resolver.ttl = 120
+	result = flattener.Sync(context.Background())
+	assert.NilError(t, result.Err)
+	assert.Equal(t, len(result.Changes), 1)
+	assert.Equal(t, result.Changes[0].Action, ChangeUpdate)
+	assert.Equal(t, *result.Changes[0].After.TTL, 120)
+	assert.Equal(t, *result.Changes[0].After.Value, "192.0.2.3")
+
+	resolver.ips = nil
+	result = flattener.Sync(context.Background())
+	assert.ErrorContains(t, result.Err, "has no addresses")
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 5)
+}
+
+func TestAliasFlattenerRun(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	resolver := &stubAliasResolver{ips: []string{"192.0.2.1"}}
+	flattener := NewAliasFlattener(api.dns(t), resolver, zone.Id, ptr("www"), "cdn.example.net")
+	flattener.MinTTL = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := flattener.Run(ctx)
+	assert.Equal(t, len((<-results).Changes), 1)
+	resolver.mu.Lock()
+	resolver.ips = []string{"192.0.2.2"}
+	resolver.mu.Unlock()
+	for result := range results {
+		if len(result.Changes) > 0 {
+			assert.Equal(t, len(result.Changes), 2)
+			break
+		}
+	}
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "www", "A"), []string{"192.0.2.2"})
+}
+
+// Serves a CNAME to an address with a shorter TTL for A queries and an address for AAAA.
+func startStubDNSServer(t *testing.T) string {
+	connection, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { connection.Close() })
+	target := dnsmessage.MustNewName("edge.cdn.example.net.")
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, address, err := connection.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			query := new(dnsmessage.Message)
+			if query.Unpack(buffer[:n]) != nil {
+				continue
+			}
+			question := query.Questions[0]
+			response := &dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.Header.ID, Response: true, RecursionAvailable: true},
+				Questions: query.Questions,
+			}
+			header := func(name dnsmessage.Name, recordType dnsmessage.Type, ttl uint32) dnsmessage.ResourceHeader {
+				return dnsmessage.ResourceHeader{Name: name, Type: recordType, Class: dnsmessage.ClassINET, TTL: ttl}
+			}
+			switch question.Type {
+			case dnsmessage.TypeA:
+				response.Answers = []dnsmessage.Resource{
+					{Header: header(question.Name, dnsmessage.TypeCNAME, 300), Body: &dnsmessage.CNAMEResource{CNAME: target}},
+					{Header: header(target, dnsmessage.TypeA, 120), Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 10}}},
+				}
+			case dnsmessage.TypeAAAA:
+				response.Answers = []dnsmessage.Resource{
+					{Header: header(question.Name, dnsmessage.TypeAAAA, 200), Body: &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}}},
+				}
+			}
+			packed, _ := response.Pack()
+			connection.WriteTo(packed, address)
+		}
+	}()
+	return connection.LocalAddr().String()
+}
+
+func TestDNSAliasResolver(t *testing.T) {
+	resolver := &DNSAliasResolver{Server: startStubDNSServer(t)}
+	ips, ttl, err := resolver.LookupAlias(context.Background(), "cdn.example.net")
+	assert.NilError(t, err)
+	assert.Equal(t, len(ips), 2)
+	assert.Equal(t, ips[0].String(), "192.0.2.10")
+	assert.Equal(t, ips[1].String(), "2001:db8::1")
+	assert.Equal(t, ttl, 120)
+}
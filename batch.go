@@ -0,0 +1,38 @@
+package gohetznerdns
+
+import (
+	"sync"
+)
+
+// Outcome of a single item of [RunConcurrently].
+type BatchResult[T, R any] struct {
+	Item  T
+	Value R
+	Err   error
+}
+
+// Calls work for every item with at most concurrency calls running at once, defaulting to 4,
+// and returns the outcomes in the order of the items. A failing item does not stop the others.
+// API calls made by work wait for the rate limit of the client, see [HetznerDNS.SetRateLimit].
+func RunConcurrently[T, R any](items []T, concurrency int, work func(item T) (R, error)) []*BatchResult[T, R] {
+	results := make([]*BatchResult[T, R], len(items))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, iif(concurrency > 0, concurrency, 4))
+	for i, item := range items {
+		i, item := i, item
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			value, err := work(item)
+			results[i] = &BatchResult[T, R]{Item: item, Value: value, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (service *recordService) GetRecordsForZones(zone_ids []*string, concurrency int) []*BatchResult[*string, []*Record] {
+	return RunConcurrently(zone_ids, concurrency, service.GetAllRecords)
+}
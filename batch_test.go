@@ -0,0 +1,97 @@
+package gohetznerdns
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestRunConcurrentlyBoundsParallelism(t *testing.T) {
+	var running, peak atomic.Int32
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	results := RunConcurrently(items, 3, func(item int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if item == 4 {
+			return 0, errors.New("four")
+		}
+		return item * 10, nil
+	})
+
+	assert.Assert(t, peak.Load() <= 3)
+	assert.Equal(t, len(results), len(items))
+	for i, result := range results {
+		assert.Equal(t, result.Item, items[i])
+		if result.Item == 4 {
+			assert.Error(t, result.Err, "four")
+			continue
+		}
+		assert.NilError(t, result.Err)
+		assert.Equal(t, result.Value, items[i]*10)
+	}
+}
+
+func TestGetRecordsForZones(t *testing.T) {
+	api := newFakeAPI(t)
+	first := api.addZone("example.com", 3600)
+	second := api.addZone("example.org", 3600)
+	api.addRecord(*first.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*second.Id, "www", "A", "192.0.2.2", nil)
+	api.addRecord(*second.Id, "mail", "A", "192.0.2.3", nil)
+	dns := api.dns(t)
+
+	results := dns.GetRecordService().GetRecordsForZones([]*string{first.Id, second.Id, ptr("")}, 2)
+	assert.Equal(t, len(results), 3)
+	assert.NilError(t, results[0].Err)
+	assert.Equal(t, len(results[0].Value), 1)
+	assert.NilError(t, results[1].Err)
+	assert.Equal(t, len(results[1].Value), 2)
+	assert.Error(t, results[2].Err, "901 : zone_id is empty")
+}
+
+func TestBatchMutationsReportPerItemErrors(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+
+	requests := []*Record{
+		{ZoneId: zone.Id, Name: ptr("a"), Type: ptr("A"), Value: ptr("192.0.2.1")},
+		{ZoneId: ptr("missing"), Name: ptr("b"), Type: ptr("A"), Value: ptr("192.0.2.2")},
+		{ZoneId: zone.Id, Name: ptr("c"), Type: ptr("A"), Value: ptr("192.0.2.3")},
+	}
+	results := RunConcurrently(requests, 2, dns.GetRecordService().CreateRecord)
+	assert.NilError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "422")
+	assert.NilError(t, results[2].Err)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 2)
+}
+
+func TestRateLimitDelaysCalls(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	dns.SetRateLimit(50, 1)
+	dns.SetCoalescing(false)
+
+	start := time.Now()
+	zoneIds := []*string{zone.Id, zone.Id, zone.Id, zone.Id, zone.Id, zone.Id}
+	for _, result := range dns.GetRecordService().GetRecordsForZones(zoneIds, 6) {
+		assert.NilError(t, result.Err)
+	}
+	// One call passes at once, the other five wait 20ms each.
+	assert.Assert(t, time.Since(start) >= 90*time.Millisecond)
+	assert.Equal(t, api.callCount("GET /records"), 6)
+
+	dns.SetRateLimit(0, 0)
+	assert.Assert(t, dns.(*hetznerDNS).client.limiter == nil)
+}
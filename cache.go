@@ -0,0 +1,163 @@
+package gohetznerdns
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Time to live of cached reads per resource, see [HetznerDNS.SetCache]. A zero TTL leaves the
+// resource uncached.
+type CacheOptions struct {
+	// Zone lists, single zones and zone file exports.
+	ZonesTTL time.Duration
+	// Record lists of a zone and single records.
+	RecordsTTL time.Duration
+}
+
+// Read-through cache of successful GET responses keyed by path and query.
+type cache struct {
+	options CacheOptions
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	now     func() time.Time
+	// Bumped by every invalidation, lets reads started before a write skip storing their response.
+	generation uint64
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+func newCache(options CacheOptions) *cache {
+	return &cache{options: options, entries: map[string]*cacheEntry{}, now: time.Now}
+}
+
+func cacheKey(path, query string) string {
+	return path + "?" + query
+}
+
+func (cache *cache) ttl(path string) time.Duration {
+	switch {
+	case strings.HasPrefix(path, zonesBasePath):
+		return cache.options.ZonesTTL
+	case strings.HasPrefix(path, recordsBasePath):
+		return cache.options.RecordsTTL
+	}
+	return 0
+}
+
+func (cache *cache) get(key string) ([]byte, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !cache.now().Before(entry.expires) {
+		delete(cache.entries, key)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// Returns the current generation, to be passed to [cache.put] once the read completes.
+func (cache *cache) currentGeneration() uint64 {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.generation
+}
+
+// Stores the response of a read started at the given generation, unless an invalidation happened
+// since then and the response may predate the write.
+func (cache *cache) put(path, key string, body []byte, generation uint64) {
+	ttl := cache.ttl(path)
+	if ttl <= 0 {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.generation != generation {
+		return
+	}
+	cache.entries[key] = &cacheEntry{body: body, expires: cache.now().Add(ttl)}
+}
+
+func (cache *cache) remove(keys ...string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, key := range keys {
+		delete(cache.entries, key)
+	}
+}
+
+func (cache *cache) removePrefix(prefix string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for key := range cache.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// Drops the entries a write to path may have made stale. Record writes invalidate the record,
+// the record list of its zone and all zones, whose record counts change. When the zone of a
+// record is unknown, all record lists are dropped.
+func (cache *cache) invalidate(path string, body interface{}) {
+	cache.mu.Lock()
+	cache.generation++
+	cache.mu.Unlock()
+	switch {
+	case strings.HasPrefix(path, recordsBasePath):
+		recordKey := cacheKey(path, "")
+		var zoneId *string
+		if record, ok := body.(*Record); ok && record != nil {
+			zoneId = record.ZoneId
+		}
+		if zoneId == nil && path != recordsBasePath {
+			if cached, ok := cache.get(recordKey); ok {
+				response := new(RecordResponse)
+				if json.Unmarshal(cached, response) == nil && response.Record != nil {
+					zoneId = response.Record.ZoneId
+				}
+			}
+		}
+		cache.removePrefix(zonesBasePath)
+		if zoneId == nil {
+			cache.removePrefix(recordsBasePath)
+			return
+		}
+		cache.remove(recordKey, recordListCacheKey(*zoneId))
+	case path == zonesBasePath+"/file/validate":
+	case strings.HasPrefix(path, zonesBasePath+"/"):
+		cache.removePrefix(zonesBasePath)
+		// Deleting or importing a zone replaces its records.
+		cache.remove(recordListCacheKey(strings.Split(strings.TrimPrefix(path, zonesBasePath+"/"), "/")[0]))
+	case strings.HasPrefix(path, zonesBasePath):
+		cache.removePrefix(zonesBasePath)
+	}
+}
+
+func recordListCacheKey(zoneId string) string {
+	return cacheKey(recordsBasePath, url.Values{"zone_id": {zoneId}}.Encode())
+}
+
+func (dns *hetznerDNS) SetCache(options *CacheOptions) {
+	if options == nil {
+		dns.client.cache = nil
+		return
+	}
+	dns.client.cache = newCache(*options)
+}
+
+func (dns *hetznerDNS) WithoutCache() HetznerDNS {
+	return &hetznerDNS{
+		client:        dns.client,
+		ZoneService:   &zoneService{client: dns.client, bypassCache: true},
+		RecordService: &recordService{client: dns.client, bypassCache: true},
+	}
+}
@@ -0,0 +1,153 @@
+package gohetznerdns
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestCacheServesRepeatedReads(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	dns := api.dns(t)
+	dns.SetCache(&CacheOptions{ZonesTTL: time.Minute, RecordsTTL: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		zones, err := dns.GetZoneService().GetAllZones()
+		assert.NilError(t, err)
+		assert.Equal(t, len(zones), 1)
+		records, err := dns.GetRecordService().GetAllRecords(zone.Id)
+		assert.NilError(t, err)
+		assert.Equal(t, len(records), 1)
+	}
+	assert.Equal(t, api.callCount("GET /zones"), 1)
+	assert.Equal(t, api.callCount("GET /records"), 1)
+
+	_, err := dns.WithoutCache().GetRecordService().GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, api.callCount("GET /records"), 2)
+
+	dns.SetCache(nil)
+	_, err = dns.GetRecordService().GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, api.callCount("GET /records"), 3)
+}
+
+func TestCacheExpires(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	dns.SetCache(&CacheOptions{ZonesTTL: time.Minute})
+	now := time.Now()
+	dns.(*hetznerDNS).client.cache.now = func() time.Time { return now }
+
+	_, err := dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	_, err = dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, api.callCount("GET /zones"), 1)
+
+	now = now.Add(time.Minute)
+	_, err = dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, api.callCount("GET /zones"), 2)
+
+	// Records have no TTL and are not cached.
+	dns.GetRecordService().GetAllRecords(zone.Id)
+	dns.GetRecordService().GetAllRecords(zone.Id)
+	assert.Equal(t, api.callCount("GET /records"), 2)
+}
+
+func TestCacheInvalidatedByWrites(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	other := api.addZone("example.org", 3600)
+	dns := api.dns(t)
+	dns.SetCache(&CacheOptions{ZonesTTL: time.Minute, RecordsTTL: time.Minute})
+	records := dns.GetRecordService()
+
+	_, err := records.GetAllRecords(other.Id)
+	assert.NilError(t, err)
+	created, err := records.CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")})
+	assert.NilError(t, err)
+	list, err := records.GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, len(list), 1)
+
+	_, err = records.GetRecord(created.Id)
+	assert.NilError(t, err)
+	assert.NilError(t, records.DeleteRecord(created.Id))
+	list, err = records.GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, len(list), 0)
+	_, err = records.GetRecord(created.Id)
+	assert.ErrorContains(t, err, "404")
+
+	// The record list of the other zone survives.
+	_, err = records.GetAllRecords(other.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, api.callCount("GET /records"), 5)
+
+	zoneBefore, err := dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	_, err = dns.GetZoneService().UpdateZone(zone.Id, &ZoneRequest{Name: zone.Name, TTL: ptr(60)})
+	assert.NilError(t, err)
+	zoneAfter, err := dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, *zoneBefore.TTL, 3600)
+	assert.Equal(t, *zoneAfter.TTL, 60)
+}
+
+func TestCacheBypassedByReadsBeforeWrites(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	record := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	dns := api.dns(t)
+	dns.SetCache(&CacheOptions{ZonesTTL: time.Minute, RecordsTTL: time.Minute})
+	var entries []*JournalEntry
+	dns.SetJournal(JournalSinkFunc(func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}), "")
+	records := dns.GetRecordService()
+	_, err := records.GetRecord(record.Id)
+	assert.NilError(t, err)
+	_, err = records.GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+
+	// Changed out of band, the cache still holds 192.0.2.1.
+	api.mu.Lock()
+	api.records[*record.Id].Value = ptr("192.0.2.2")
+	api.mu.Unlock()
+
+	_, err = records.UpdateRecord(&Record{Id: record.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.3")})
+	assert.NilError(t, err)
+	assert.Equal(t, *entries[0].RecordBefore.Value, "192.0.2.2")
+	_, err = records.GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+
+	api.mu.Lock()
+	api.records[*record.Id].Value = ptr("192.0.2.4")
+	api.mu.Unlock()
+	_, result, err := records.EnsureRecord(zone.Id, ptr("www"), ptr("A"), ptr("192.0.2.4"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUnchanged)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+}
+
+func TestCacheSkipsReadsStartedBeforeInvalidation(t *testing.T) {
+	cache := newCache(CacheOptions{RecordsTTL: time.Minute})
+	key := recordListCacheKey("zone")
+
+	generation := cache.currentGeneration()
+	cache.invalidate(recordsBasePath, &Record{ZoneId: ptr("zone")})
+	cache.put(recordsBasePath, key, []byte(`{"records":[]}`), generation)
+	_, ok := cache.get(key)
+	assert.Assert(t, !ok)
+
+	cache.put(recordsBasePath, key, []byte(`{"records":[]}`), cache.currentGeneration())
+	_, ok = cache.get(key)
+	assert.Assert(t, ok)
+}
@@ -1,12 +1,15 @@
 package gohetznerdns
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"net/http"
 	"net/url"
 	"slices"
 
 	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -19,9 +22,15 @@ const (
 )
 
 type client struct {
-	client  *resty.Client
-	baseURL *url.URL
-	token   string
+	client    *resty.Client
+	baseURL   *url.URL
+	token     string
+	journal   *journal
+	cache     *cache
+	coalescer *coalescer
+	// Lets identical reads in flight share one call.
+	coalesce bool
+	limiter  *rate.Limiter
 }
 
 type request struct {
@@ -29,10 +38,15 @@ type request struct {
 	baseURL             *url.URL
 	expectedStatusCodes []int
 	result              interface{}
+	cache               *cache
+	bypassCache         bool
+	coalescer           *coalescer
+	coalesce            bool
+	limiter             *rate.Limiter
 }
 
 func newClient() *client {
-	client := &client{client: resty.New()}
+	client := &client{client: resty.New(), coalescer: newCoalescer(), coalesce: true}
 	client.setBaseURL(defaultBaseURL)
 	return client
 }
@@ -54,6 +68,10 @@ func (c *client) createRequest(contentType string, expectedStatusCodes ...int) *
 		request:             c.client.R(),
 		baseURL:             c.baseURL,
 		expectedStatusCodes: expectedStatusCodes,
+		cache:               c.cache,
+		coalescer:           c.coalescer,
+		coalesce:            c.coalesce,
+		limiter:             c.limiter,
 	}
 	request.request.SetHeader("Content-Type", contentType).
 		SetHeader("Auth-API-Token", c.token)
@@ -78,6 +96,12 @@ func (r *request) setBody(body interface{}) *request {
 	return r
 }
 
+// Skips the cache lookup of a read, the response still refreshes the cache.
+func (r *request) setBypassCache(bypass bool) *request {
+	r.bypassCache = bypass
+	return r
+}
+
 func (r *request) setResult(result interface{}) *request {
 	r.result = result
 	return r
@@ -89,19 +113,77 @@ func (r *request) execute(method, path string) ([]byte, error) {
 	if u, err = r.baseURL.Parse(basePath + path); err != nil {
 		return nil, err
 	}
-	response, err := r.request.Execute(method, u.String())
+	key := cacheKey(path, r.request.QueryParam.Encode())
+	var generation uint64
+	if r.cache != nil && method == "GET" {
+		if !r.bypassCache {
+			if body, ok := r.cache.get(key); ok {
+				return body, r.unmarshal(body)
+			}
+		}
+		generation = r.cache.currentGeneration()
+	}
+	if r.cache != nil && method != "GET" {
+		defer r.cache.invalidate(path, r.request.Body)
+	}
+	if method != "GET" {
+		// Reads started during or after the write must not join reads started before it.
+		r.coalescer.forget()
+		defer r.coalescer.forget()
+	}
+	fetch := func() (*response, error) {
+		if r.limiter != nil {
+			if err := r.limiter.Wait(context.Background()); err != nil {
+				return nil, err
+			}
+		}
+		result, err := r.request.Execute(method, u.String())
+		if err != nil {
+			return nil, err
+		}
+		return &response{body: result.Body(), statusCode: result.StatusCode(), status: result.Status()}, nil
+	}
+	var response *response
+	if method == "GET" && r.coalesce {
+		response, err = r.coalescer.do(key, fetch)
+	} else {
+		response, err = fetch()
+	}
 	if err != nil {
 		return nil, err
 	}
-	body := response.Body()
-	if r.result != nil && body != nil {
-		if err := json.Unmarshal(response.Body(), r.result); err != nil {
-			return body, err
-		}
+	body := response.body
+	if err := r.unmarshal(body); err != nil {
+		return body, err
 	}
-	if slices.Contains(r.expectedStatusCodes, response.StatusCode()) {
+	if slices.Contains(r.expectedStatusCodes, response.statusCode) {
+		if r.cache != nil && method == "GET" && response.statusCode == 200 {
+			r.cache.put(path, key, body, generation)
+		}
 		return body, nil
 	}
 
-	return body, fmt.Errorf(response.Status())
+	return body, &statusError{code: response.statusCode, status: response.status}
+}
+
+// Error of a response with an unexpected status code.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (err *statusError) Error() string {
+	return err.status
+}
+
+func isNotFound(err error) bool {
+	var status *statusError
+	return errors.As(err, &status) && status.code == http.StatusNotFound
+}
+
+func (r *request) unmarshal(body []byte) error {
+	if r.result == nil || body == nil {
+		return nil
+	}
+	return json.Unmarshal(body, r.result)
 }
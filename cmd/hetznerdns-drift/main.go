@@ -0,0 +1,53 @@
+// Command hetznerdns-drift compares the zones and records of a Hetzner DNS account with a
+// saved snapshot. It is meant to run from a scheduler and exits with status 1 on drift and
+// status 2 on errors. The API token is read from HETZNER_DNS_TOKEN.
+//
+//	hetznerdns-drift -snapshot dns.json -save   # records the current state
+//	hetznerdns-drift -snapshot dns.json         # reports changes since then
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opsheaven/gohetznerdns"
+)
+
+func main() {
+	path := flag.String("snapshot", "hetznerdns-snapshot.json", "snapshot file")
+	save := flag.Bool("save", false, "write the current state to the snapshot file and exit")
+	flag.Parse()
+
+	client, err := gohetznerdns.NewClient(os.Getenv("HETZNER_DNS_TOKEN"))
+	if err != nil {
+		fail(err)
+	}
+	if *save {
+		snapshot, err := gohetznerdns.TakeSnapshot(client)
+		if err != nil {
+			fail(err)
+		}
+		if err := snapshot.Save(*path); err != nil {
+			fail(err)
+		}
+		return
+	}
+	snapshot, err := gohetznerdns.LoadSnapshot(*path)
+	if err != nil {
+		fail(err)
+	}
+	report, err := gohetznerdns.DetectDrift(client, snapshot)
+	if err != nil {
+		fail(err)
+	}
+	if report.HasDrift() {
+		fmt.Println(report)
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
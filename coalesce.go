@@ -0,0 +1,82 @@
+package gohetznerdns
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Counts of the reads seen by the coalescing of identical reads, see [HetznerDNS.SetCoalescing].
+type CoalescingStats struct {
+	// Reads sent to the API while coalescing was enabled.
+	Requests int64
+	// Reads answered with the response of an identical read already in flight.
+	Coalesced int64
+}
+
+// Raw API response shared by coalesced reads.
+type response struct {
+	body       []byte
+	statusCode int
+	status     string
+}
+
+// Lets identical reads in flight at the same time share a single API call.
+type coalescer struct {
+	mu        sync.Mutex
+	calls     map[string]*coalescedCall
+	requests  atomic.Int64
+	coalesced atomic.Int64
+}
+
+type coalescedCall struct {
+	done     chan struct{}
+	response *response
+	err      error
+}
+
+func newCoalescer() *coalescer {
+	return &coalescer{calls: map[string]*coalescedCall{}}
+}
+
+// Runs fetch, or waits for the call of the same key already in flight and returns its outcome.
+func (coalescer *coalescer) do(key string, fetch func() (*response, error)) (*response, error) {
+	coalescer.mu.Lock()
+	if call, ok := coalescer.calls[key]; ok {
+		coalescer.mu.Unlock()
+		coalescer.coalesced.Add(1)
+		<-call.done
+		return call.response, call.err
+	}
+	call := &coalescedCall{done: make(chan struct{})}
+	coalescer.calls[key] = call
+	coalescer.mu.Unlock()
+
+	coalescer.requests.Add(1)
+	call.response, call.err = fetch()
+	coalescer.mu.Lock()
+	if coalescer.calls[key] == call {
+		delete(coalescer.calls, key)
+	}
+	coalescer.mu.Unlock()
+	close(call.done)
+	return call.response, call.err
+}
+
+// Keeps reads started from now on from joining calls in flight, which may predate a write.
+func (coalescer *coalescer) forget() {
+	coalescer.mu.Lock()
+	defer coalescer.mu.Unlock()
+	clear(coalescer.calls)
+}
+
+func (coalescer *coalescer) stats() CoalescingStats {
+	return CoalescingStats{Requests: coalescer.requests.Load(), Coalesced: coalescer.coalesced.Load()}
+}
+
+func (dns *hetznerDNS) SetCoalescing(enabled bool) {
+	dns.client.coalesce = enabled
+}
+
+func (dns *hetznerDNS) CoalescingStats() CoalescingStats {
+	return dns.client.coalescer.stats()
+}
@@ -0,0 +1,70 @@
+package gohetznerdns
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestCoalescingSharesInFlightReads(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	release := make(chan struct{})
+	api.failOn = func(method, path string, body []byte) bool {
+		<-release
+		return false
+	}
+
+	var wg sync.WaitGroup
+	zones := make([]*Zone, 10)
+	errs := make([]error, 10)
+	for i := range zones {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			zones[i], errs[i] = dns.GetZoneService().GetZoneById(zone.Id)
+		}()
+	}
+	for dns.CoalescingStats() != (CoalescingStats{Requests: 1, Coalesced: 9}) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range zones {
+		assert.NilError(t, errs[i])
+		assert.Equal(t, *zones[i].Name, "example.com")
+	}
+	assert.Equal(t, api.callCount("GET /zones"), 1)
+
+	// Reads differing in their query are not shared.
+	_, err := dns.GetRecordService().GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+	_, err = dns.GetRecordService().GetAllRecords(ptr("other"))
+	assert.NilError(t, err)
+	assert.Equal(t, dns.CoalescingStats(), CoalescingStats{Requests: 3, Coalesced: 9})
+}
+
+func TestCoalescingDisabled(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	dns.SetCoalescing(false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := dns.GetRecordService().GetAllRecords(zone.Id)
+			assert.Check(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, api.callCount("GET /records"), 5)
+	assert.Equal(t, dns.CoalescingStats(), CoalescingStats{})
+}
@@ -0,0 +1,118 @@
+package gohetznerdns
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const dkimVersion = "DKIM1"
+
+// DomainKeys Identified Mail public key record (RFC 6376). PublicKey holds the base64 encoded
+// key data: a DER encoded SubjectPublicKeyInfo for rsa and the raw key for ed25519. An empty
+// PublicKey revokes the selector.
+type DKIM struct {
+	Selector  string
+	KeyType   string
+	PublicKey string
+	Hash      []string
+	Flags     []string
+	Notes     string
+}
+
+// Parses a DKIM TXT value, quoted or unquoted, published under the given selector.
+func ParseDKIM(selector string, value string) (*DKIM, error) {
+	dkim := &DKIM{Selector: selector, KeyType: "rsa"}
+	hasKey := false
+	for i, tag := range strings.Split(unquoteTXT(value), ";") {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			return nil, fmt.Errorf("dkim: invalid tag %q", strings.TrimSpace(tag))
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "v":
+			if i != 0 || value != dkimVersion {
+				return nil, fmt.Errorf("dkim: invalid version %q", value)
+			}
+		case "k":
+			dkim.KeyType = strings.ToLower(value)
+		case "p":
+			dkim.PublicKey = strings.Join(strings.Fields(value), "")
+			hasKey = true
+		case "h":
+			dkim.Hash = strings.Split(value, ":")
+		case "t":
+			dkim.Flags = strings.Split(value, ":")
+		case "n":
+			dkim.Notes = value
+		}
+	}
+	if !hasKey {
+		return nil, fmt.Errorf("dkim: missing p tag")
+	}
+	return dkim, nil
+}
+
+func (dkim *DKIM) String() string {
+	tags := []string{"v=" + dkimVersion}
+	if len(dkim.Hash) > 0 {
+		tags = append(tags, "h="+strings.Join(dkim.Hash, ":"))
+	}
+	tags = append(tags, "k="+iif(dkim.KeyType == "", "rsa", dkim.KeyType))
+	if dkim.Notes != "" {
+		tags = append(tags, "n="+dkim.Notes)
+	}
+	if len(dkim.Flags) > 0 {
+		tags = append(tags, "t="+strings.Join(dkim.Flags, ":"))
+	}
+	tags = append(tags, "p="+dkim.PublicKey)
+	return strings.Join(tags, "; ")
+}
+
+// Validates the selector, key type and that the public key decodes to a key of that type.
+func (dkim *DKIM) Validate() error {
+	if strings.TrimSpace(dkim.Selector) == "" || strings.ContainsAny(dkim.Selector, " ;") {
+		return fmt.Errorf("dkim: invalid selector %q", dkim.Selector)
+	}
+	if dkim.PublicKey == "" {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(dkim.PublicKey)
+	if err != nil {
+		return fmt.Errorf("dkim: public key is not valid base64")
+	}
+	switch iif(dkim.KeyType == "", "rsa", dkim.KeyType) {
+	case "rsa":
+		key, err := x509.ParsePKIXPublicKey(data)
+		if err != nil {
+			return fmt.Errorf("dkim: invalid rsa public key: %w", err)
+		}
+		if _, ok := key.(*rsa.PublicKey); !ok {
+			return fmt.Errorf("dkim: public key is not an rsa key")
+		}
+	case "ed25519":
+		if len(data) != ed25519.PublicKeySize {
+			return fmt.Errorf("dkim: ed25519 public key must be %d bytes", ed25519.PublicKeySize)
+		}
+	default:
+		return fmt.Errorf("dkim: unsupported key type %q", dkim.KeyType)
+	}
+	return nil
+}
+
+// Returns the key as a TXT record below the given owner name, e.g. "@" for the zone apex.
+func (dkim *DKIM) Record(name string) *Record {
+	return &Record{Name: ptr(dkimRecordName(dkim.Selector, name)), Type: ptr("TXT"), Value: ptr(dkim.String())}
+}
+
+func dkimRecordName(selector, name string) string {
+	name = normalizeRecordName(name)
+	return selector + "._domainkey" + iif(name == "@", "", "."+name)
+}
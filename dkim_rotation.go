@@ -0,0 +1,260 @@
+package gohetznerdns
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Destination of generated DKIM private keys.
+type DKIMKeySink interface {
+	// Stores the PEM encoded PKCS #8 private key of the selector.
+	StoreKey(domain, selector string, privateKey []byte) error
+}
+
+// Adapts a function to a [DKIMKeySink].
+type DKIMKeySinkFunc func(domain, selector string, privateKey []byte) error
+
+func (sink DKIMKeySinkFunc) StoreKey(domain, selector string, privateKey []byte) error {
+	return sink(domain, selector, privateKey)
+}
+
+// Writes private keys to <Dir>/<domain>/<selector>.pem readable only by the owner.
+type FileDKIMKeySink struct {
+	Dir string
+}
+
+func (sink *FileDKIMKeySink) StoreKey(domain, selector string, privateKey []byte) error {
+	dir := filepath.Join(sink.Dir, domain)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, selector+".pem"), privateKey, 0o600)
+}
+
+// Rotation progress of a zone. Pending holds a selector whose key was stored but whose record
+// may not be published yet, Previous a selector waiting for removal after RetireAfter.
+type DKIMRotationState struct {
+	ZoneId        string    `json:"zone_id"`
+	Domain        string    `json:"domain"`
+	Current       string    `json:"current,omitempty"`
+	Previous      string    `json:"previous,omitempty"`
+	Pending       string    `json:"pending,omitempty"`
+	PendingRecord string    `json:"pending_record,omitempty"`
+	RetireAfter   time.Time `json:"retire_after,omitempty"`
+}
+
+// Persists rotation state between runs.
+type DKIMStateStore interface {
+	// Returns the state of the zone, or nil when the zone was never rotated.
+	Load(zoneId string) (*DKIMRotationState, error)
+	Save(state *DKIMRotationState) error
+}
+
+// Keeps the rotation state of all zones in a single JSON file.
+type FileDKIMStateStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (store *FileDKIMStateStore) read() (map[string]*DKIMRotationState, error) {
+	states := map[string]*DKIMRotationState{}
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return states, json.Unmarshal(data, &states)
+}
+
+func (store *FileDKIMStateStore) Load(zoneId string) (*DKIMRotationState, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	states, err := store.read()
+	if err != nil {
+		return nil, err
+	}
+	return states[zoneId], nil
+}
+
+func (store *FileDKIMStateStore) Save(state *DKIMRotationState) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	states, err := store.read()
+	if err != nil {
+		return err
+	}
+	states[state.ZoneId] = state
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(store.Path, data, 0o600)
+}
+
+// Writes the file through a temporary file so a crash never leaves it half written.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Generates DKIM keys, publishes their selectors and removes the old selector after an
+// overlap period. Every step is recorded in the state store so an interrupted rotation
+// resumes where it stopped.
+type DKIMRotator struct {
+	dns   HetznerDNS
+	sink  DKIMKeySink
+	store DKIMStateStore
+
+	// Key type, "rsa" (default) or "ed25519".
+	KeyType string
+	// RSA key size, defaults to 2048.
+	RSABits int
+	// Time both selectors stay published, defaults to 7 days.
+	Overlap time.Duration
+	// TTL of the published records, nil uses the zone default.
+	TTL *int
+	// Returns the selector for a new key, defaults to "s" followed by the date.
+	Selector func(now time.Time) string
+
+	now func() time.Time
+}
+
+// Creates a rotator storing private keys in sink and state in store.
+func NewDKIMRotator(dns HetznerDNS, sink DKIMKeySink, store DKIMStateStore) *DKIMRotator {
+	return &DKIMRotator{
+		dns:     dns.WithoutCache(),
+		sink:    sink,
+		store:   store,
+		KeyType: "rsa",
+		RSABits: 2048,
+		Overlap: 7 * 24 * time.Hour,
+		Selector: func(now time.Time) string {
+			return "s" + now.UTC().Format("20060102")
+		},
+		now: time.Now,
+	}
+}
+
+// Generates a key pair and returns the PEM encoded private key and the public DKIM record.
+func GenerateDKIMKey(selector, keyType string, rsaBits int) ([]byte, *DKIM, error) {
+	var private crypto.PrivateKey
+	var public []byte
+	switch keyType {
+	case "", "rsa":
+		key, err := rsa.GenerateKey(rand.Reader, iif(rsaBits == 0, 2048, rsaBits))
+		if err != nil {
+			return nil, nil, err
+		}
+		if public, err = x509.MarshalPKIXPublicKey(&key.PublicKey); err != nil {
+			return nil, nil, err
+		}
+		private, keyType = key, "rsa"
+	case "ed25519":
+		publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		private, public = key, publicKey
+	default:
+		return nil, nil, fmt.Errorf("dkim: unsupported key type %q", keyType)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+	dkim := &DKIM{Selector: selector, KeyType: keyType, PublicKey: base64.StdEncoding.EncodeToString(public)}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), dkim, nil
+}
+
+func (rotator *DKIMRotator) load(zoneId *string) (*DKIMRotationState, error) {
+	if err := validateNotEmpty("zoneId", zoneId); err != nil {
+		return nil, err
+	}
+	state, err := rotator.store.Load(*zoneId)
+	if err != nil || state != nil {
+		return state, err
+	}
+	zone, err := rotator.dns.GetZoneService().GetZoneById(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	return &DKIMRotationState{ZoneId: *zoneId, Domain: deref(zone.Name)}, nil
+}
+
+// Completes an interrupted publication and removes the previous selector once the overlap
+// has passed. It never starts a new rotation.
+func (rotator *DKIMRotator) Advance(zoneId *string) (*DKIMRotationState, error) {
+	state, err := rotator.load(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	records := rotator.dns.GetRecordService()
+	if state.Pending != "" {
+		name := dkimRecordName(state.Pending, "@")
+		if _, _, err := records.EnsureRecord(zoneId, &name, ptr("TXT"), &state.PendingRecord, rotator.TTL); err != nil {
+			return state, err
+		}
+		state.Previous = iif(state.Current != "", state.Current, state.Previous)
+		state.Current, state.Pending, state.PendingRecord = state.Pending, "", ""
+		state.RetireAfter = rotator.now().Add(rotator.Overlap)
+		if err := rotator.store.Save(state); err != nil {
+			return state, err
+		}
+	}
+	if state.Previous != "" && !rotator.now().Before(state.RetireAfter) {
+		name := dkimRecordName(state.Previous, "@")
+		if _, err := records.EnsureAbsent(zoneId, &name, ptr("TXT"), nil); err != nil {
+			return state, err
+		}
+		state.Previous, state.RetireAfter = "", time.Time{}
+		if err := rotator.store.Save(state); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+// Starts a new rotation: generates a key, hands the private key to the sink and publishes the
+// new selector. Fails while the previous rotation is still within its overlap period.
+func (rotator *DKIMRotator) Rotate(zoneId *string) (*DKIMRotationState, error) {
+	state, err := rotator.Advance(zoneId)
+	if err != nil {
+		return state, err
+	}
+	if state.Previous != "" {
+		return state, fmt.Errorf("dkim: rotation of %s in progress until %s", state.Domain, state.RetireAfter.Format(time.RFC3339))
+	}
+	selector := rotator.Selector(rotator.now())
+	if selector == state.Current {
+		return state, fmt.Errorf("dkim: selector %s is already in use", selector)
+	}
+	privateKey, dkim, err := GenerateDKIMKey(selector, rotator.KeyType, rotator.RSABits)
+	if err != nil {
+		return state, err
+	}
+	if err := rotator.sink.StoreKey(state.Domain, selector, privateKey); err != nil {
+		return state, err
+	}
+	state.Pending, state.PendingRecord = selector, dkim.String()
+	if err := rotator.store.Save(state); err != nil {
+		return state, err
+	}
+	return rotator.Advance(zoneId)
+}
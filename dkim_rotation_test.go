@@ -0,0 +1,119 @@
+package gohetznerdns
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestGenerateDKIMKey(t *testing.T) {
+	privateKey, dkim, err := GenerateDKIMKey("s1", "rsa", 1024)
+	assert.NilError(t, err)
+	assert.NilError(t, dkim.Validate())
+	block, _ := pem.Decode(privateKey)
+	_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.NilError(t, err)
+
+	_, dkim, err = GenerateDKIMKey("s2", "ed25519", 0)
+	assert.NilError(t, err)
+	assert.Equal(t, dkim.KeyType, "ed25519")
+	assert.NilError(t, dkim.Validate())
+
+	_, _, err = GenerateDKIMKey("s3", "dsa", 0)
+	assert.Error(t, err, "dkim: unsupported key type \"dsa\"")
+}
+
+func TestFileDKIMKeySink(t *testing.T) {
+	dir := t.TempDir()
+	sink := &FileDKIMKeySink{Dir: dir}
+	assert.NilError(t, sink.StoreKey("example.com", "s1", []byte("key")))
+	info, err := os.Stat(filepath.Join(dir, "example.com", "s1.pem"))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0o600))
+}
+
+func TestDKIMRotation(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	keys := map[string][]byte{}
+	sink := DKIMKeySinkFunc(func(domain, selector string, privateKey []byte) error {
+		keys[domain+"/"+selector] = privateKey
+		return nil
+	})
+	store := &FileDKIMStateStore{Path: filepath.Join(t.TempDir(), "dkim.json")}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotator := NewDKIMRotator(api.dns(t), sink, store)
+	rotator.KeyType = "ed25519"
+	rotator.Overlap = 48 * time.Hour
+	rotator.now = func() time.Time { return now }
+
+	state, err := rotator.Rotate(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, state.Current, "s20240101")
+	assert.Assert(t, keys["example.com/s20240101"] != nil)
+	assert.Equal(t, len(recordValues(api.zoneRecords(*zone.Id), "s20240101._domainkey", "TXT")), 1)
+
+	now = now.Add(24 * time.Hour)
+	state, err = rotator.Rotate(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, state.Current, "s20240102")
+	assert.Equal(t, state.Previous, "s20240101")
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 2)
+
+	now = now.Add(24 * time.Hour)
+	_, err = rotator.Rotate(zone.Id)
+	assert.Error(t, err, "dkim: rotation of example.com in progress until 2024-01-04T00:00:00Z")
+
+	state, err = rotator.Advance(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, state.Previous, "s20240101")
+
+	now = now.Add(24 * time.Hour)
+	state, err = rotator.Advance(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, state.Previous, "")
+	records := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, *records[0].Name, "s20240102._domainkey")
+}
+
+func TestDKIMRotationResumesPendingPublication(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	store := &FileDKIMStateStore{Path: filepath.Join(t.TempDir(), "dkim.json")}
+	sink := DKIMKeySinkFunc(func(domain, selector string, privateKey []byte) error { return nil })
+	api.failOn = func(method, path string, body []byte) bool { return method == "POST" }
+	rotator := NewDKIMRotator(api.dns(t), sink, store)
+	rotator.KeyType = "ed25519"
+
+	_, err := rotator.Rotate(zone.Id)
+	assert.Error(t, err, "500 Internal Server Error")
+	saved, err := store.Load(*zone.Id)
+	assert.NilError(t, err)
+	assert.Assert(t, saved.Pending != "")
+
+	api.failOn = nil
+	state, err := rotator.Advance(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, state.Current, saved.Pending)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+}
+
+func TestDKIMRotationSinkFailure(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	store := &FileDKIMStateStore{Path: filepath.Join(t.TempDir(), "dkim.json")}
+	sink := DKIMKeySinkFunc(func(domain, selector string, privateKey []byte) error { return errors.New("vault unavailable") })
+	rotator := NewDKIMRotator(api.dns(t), sink, store)
+	rotator.KeyType = "ed25519"
+
+	_, err := rotator.Rotate(zone.Id)
+	assert.Error(t, err, "vault unavailable")
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 0)
+}
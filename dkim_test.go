@@ -0,0 +1,59 @@
+package gohetznerdns
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseDKIM(t *testing.T) {
+	dkim, err := ParseDKIM("google", `"v=DKIM1; k=rsa; h=sha256; t=y:s; p=MIIBIjAN" "BgkqhkiG"`)
+	assert.NilError(t, err)
+	assert.Equal(t, dkim.Selector, "google")
+	assert.Equal(t, dkim.KeyType, "rsa")
+	assert.Equal(t, dkim.PublicKey, "MIIBIjANBgkqhkiG")
+	assert.DeepEqual(t, dkim.Hash, []string{"sha256"})
+	assert.DeepEqual(t, dkim.Flags, []string{"y", "s"})
+	assert.Equal(t, dkim.String(), "v=DKIM1; h=sha256; k=rsa; t=y:s; p=MIIBIjANBgkqhkiG")
+}
+
+func TestParseDKIMErrors(t *testing.T) {
+	_, err := ParseDKIM("s1", "v=DKIM1; k=rsa")
+	assert.Error(t, err, "dkim: missing p tag")
+	_, err = ParseDKIM("s1", "k=rsa; v=DKIM1; p=")
+	assert.Error(t, err, "dkim: invalid version \"DKIM1\"")
+	_, err = ParseDKIM("s1", "v=DKIM1; p")
+	assert.Error(t, err, "dkim: invalid tag \"p\"")
+}
+
+func TestValidateDKIM(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	der, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	dkim := &DKIM{Selector: "s1", KeyType: "rsa", PublicKey: base64.StdEncoding.EncodeToString(der)}
+	assert.NilError(t, dkim.Validate())
+
+	edKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	dkim = &DKIM{Selector: "s2", KeyType: "ed25519", PublicKey: base64.StdEncoding.EncodeToString(edKey)}
+	assert.NilError(t, dkim.Validate())
+
+	der, _ = x509.MarshalPKIXPublicKey(edKey)
+	dkim = &DKIM{Selector: "s3", KeyType: "rsa", PublicKey: base64.StdEncoding.EncodeToString(der)}
+	assert.Error(t, dkim.Validate(), "dkim: public key is not an rsa key")
+
+	assert.Error(t, (&DKIM{Selector: "a b"}).Validate(), "dkim: invalid selector \"a b\"")
+	assert.Error(t, (&DKIM{Selector: "s", PublicKey: "***"}).Validate(), "dkim: public key is not valid base64")
+	assert.Error(t, (&DKIM{Selector: "s", KeyType: "dsa", PublicKey: "AAAA"}).Validate(), "dkim: unsupported key type \"dsa\"")
+	assert.NilError(t, (&DKIM{Selector: "revoked"}).Validate())
+}
+
+func TestDKIMRecord(t *testing.T) {
+	dkim := &DKIM{Selector: "s1", KeyType: "ed25519", PublicKey: "AAAA"}
+	assert.Equal(t, *dkim.Record("@").Name, "s1._domainkey")
+	assert.Equal(t, *dkim.Record("mail").Name, "s1._domainkey.mail")
+	assert.Equal(t, *dkim.Record("@").Value, "v=DKIM1; k=ed25519; p=AAAA")
+}
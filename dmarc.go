@@ -0,0 +1,144 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const dmarcVersion = "DMARC1"
+
+var dmarcPolicies = []string{"none", "quarantine", "reject"}
+
+var dmarcAlignments = []string{"r", "s"}
+
+// Domain-based Message Authentication, Reporting and Conformance policy (RFC 7489).
+// Percent and ReportInterval are nil when the tag is not set.
+type DMARC struct {
+	Policy          string
+	SubdomainPolicy string
+	Percent         *int
+	RUA             []string
+	RUF             []string
+	DKIMAlignment   string
+	SPFAlignment    string
+	FailureOptions  string
+	ReportInterval  *int
+}
+
+// Parses a DMARC TXT value, quoted or unquoted.
+func ParseDMARC(value string) (*DMARC, error) {
+	tags := strings.Split(unquoteTXT(value), ";")
+	version := strings.TrimSpace(tags[0])
+	if !strings.EqualFold(strings.ReplaceAll(version, " ", ""), "v="+dmarcVersion) {
+		return nil, fmt.Errorf("dmarc: value does not start with v=%s", dmarcVersion)
+	}
+	dmarc := &DMARC{}
+	for _, tag := range tags[1:] {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			return nil, fmt.Errorf("dmarc: invalid tag %q", strings.TrimSpace(tag))
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "p":
+			dmarc.Policy = strings.ToLower(value)
+		case "sp":
+			dmarc.SubdomainPolicy = strings.ToLower(value)
+		case "pct", "ri":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("dmarc: invalid %s %q", key, value)
+			}
+			if key == "pct" {
+				dmarc.Percent = &number
+			} else {
+				dmarc.ReportInterval = &number
+			}
+		case "rua", "ruf":
+			var uris []string
+			for _, uri := range strings.Split(value, ",") {
+				uris = append(uris, strings.TrimSpace(uri))
+			}
+			if key == "rua" {
+				dmarc.RUA = uris
+			} else {
+				dmarc.RUF = uris
+			}
+		case "adkim":
+			dmarc.DKIMAlignment = strings.ToLower(value)
+		case "aspf":
+			dmarc.SPFAlignment = strings.ToLower(value)
+		case "fo":
+			dmarc.FailureOptions = value
+		}
+	}
+	return dmarc, nil
+}
+
+func (dmarc *DMARC) String() string {
+	tags := []string{"v=" + dmarcVersion, "p=" + dmarc.Policy}
+	if dmarc.SubdomainPolicy != "" {
+		tags = append(tags, "sp="+dmarc.SubdomainPolicy)
+	}
+	if dmarc.Percent != nil {
+		tags = append(tags, "pct="+strconv.Itoa(*dmarc.Percent))
+	}
+	if len(dmarc.RUA) > 0 {
+		tags = append(tags, "rua="+strings.Join(dmarc.RUA, ","))
+	}
+	if len(dmarc.RUF) > 0 {
+		tags = append(tags, "ruf="+strings.Join(dmarc.RUF, ","))
+	}
+	if dmarc.DKIMAlignment != "" {
+		tags = append(tags, "adkim="+dmarc.DKIMAlignment)
+	}
+	if dmarc.SPFAlignment != "" {
+		tags = append(tags, "aspf="+dmarc.SPFAlignment)
+	}
+	if dmarc.FailureOptions != "" {
+		tags = append(tags, "fo="+dmarc.FailureOptions)
+	}
+	if dmarc.ReportInterval != nil {
+		tags = append(tags, "ri="+strconv.Itoa(*dmarc.ReportInterval))
+	}
+	return strings.Join(tags, "; ")
+}
+
+// Validates policies, percentage, alignment modes and report URIs.
+func (dmarc *DMARC) Validate() error {
+	if !containsFold(dmarcPolicies, dmarc.Policy) {
+		return fmt.Errorf("dmarc: invalid policy %q", dmarc.Policy)
+	}
+	if dmarc.SubdomainPolicy != "" && !containsFold(dmarcPolicies, dmarc.SubdomainPolicy) {
+		return fmt.Errorf("dmarc: invalid subdomain policy %q", dmarc.SubdomainPolicy)
+	}
+	if dmarc.Percent != nil && (*dmarc.Percent < 0 || *dmarc.Percent > 100) {
+		return fmt.Errorf("dmarc: pct %d is not between 0 and 100", *dmarc.Percent)
+	}
+	if dmarc.DKIMAlignment != "" && !containsFold(dmarcAlignments, dmarc.DKIMAlignment) {
+		return fmt.Errorf("dmarc: invalid adkim %q", dmarc.DKIMAlignment)
+	}
+	if dmarc.SPFAlignment != "" && !containsFold(dmarcAlignments, dmarc.SPFAlignment) {
+		return fmt.Errorf("dmarc: invalid aspf %q", dmarc.SPFAlignment)
+	}
+	for _, uri := range append(append([]string{}, dmarc.RUA...), dmarc.RUF...) {
+		if !strings.HasPrefix(strings.ToLower(uri), "mailto:") && !strings.HasPrefix(strings.ToLower(uri), "https:") {
+			return fmt.Errorf("dmarc: invalid report uri %q", uri)
+		}
+	}
+	return nil
+}
+
+// Returns the policy as a TXT record for the given owner name, e.g. "@" for the zone apex.
+func (dmarc *DMARC) Record(name string) *Record {
+	return &Record{Name: ptr(dmarcRecordName(name)), Type: ptr("TXT"), Value: ptr(dmarc.String())}
+}
+
+func dmarcRecordName(name string) string {
+	name = normalizeRecordName(name)
+	return iif(name == "@", "_dmarc", "_dmarc."+name)
+}
@@ -0,0 +1,45 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseDMARC(t *testing.T) {
+	dmarc, err := ParseDMARC(`"v=DMARC1; p=Quarantine; sp=reject; pct=50; rua=mailto:a@example.com, mailto:b@example.com; ruf=mailto:f@example.com; adkim=s; aspf=r; fo=1; ri=3600"`)
+	assert.NilError(t, err)
+	assert.Equal(t, dmarc.Policy, "quarantine")
+	assert.Equal(t, dmarc.SubdomainPolicy, "reject")
+	assert.Equal(t, *dmarc.Percent, 50)
+	assert.DeepEqual(t, dmarc.RUA, []string{"mailto:a@example.com", "mailto:b@example.com"})
+	assert.DeepEqual(t, dmarc.RUF, []string{"mailto:f@example.com"})
+	assert.Equal(t, dmarc.DKIMAlignment, "s")
+	assert.Equal(t, dmarc.SPFAlignment, "r")
+	assert.Equal(t, *dmarc.ReportInterval, 3600)
+	assert.NilError(t, dmarc.Validate())
+	assert.Equal(t, dmarc.String(), "v=DMARC1; p=quarantine; sp=reject; pct=50; rua=mailto:a@example.com,mailto:b@example.com; ruf=mailto:f@example.com; adkim=s; aspf=r; fo=1; ri=3600")
+}
+
+func TestParseDMARCErrors(t *testing.T) {
+	_, err := ParseDMARC("v=spf1 -all")
+	assert.Error(t, err, "dmarc: value does not start with v=DMARC1")
+	_, err = ParseDMARC("v=DMARC1; p")
+	assert.Error(t, err, "dmarc: invalid tag \"p\"")
+	_, err = ParseDMARC("v=DMARC1; p=none; pct=all")
+	assert.Error(t, err, "dmarc: invalid pct \"all\"")
+}
+
+func TestValidateDMARC(t *testing.T) {
+	assert.Error(t, (&DMARC{Policy: "block"}).Validate(), "dmarc: invalid policy \"block\"")
+	assert.Error(t, (&DMARC{Policy: "none", Percent: ptr(120)}).Validate(), "dmarc: pct 120 is not between 0 and 100")
+	assert.Error(t, (&DMARC{Policy: "none", DKIMAlignment: "x"}).Validate(), "dmarc: invalid adkim \"x\"")
+	assert.Error(t, (&DMARC{Policy: "none", RUA: []string{"a@example.com"}}).Validate(), "dmarc: invalid report uri \"a@example.com\"")
+}
+
+func TestDMARCRecord(t *testing.T) {
+	dmarc := &DMARC{Policy: "reject"}
+	assert.Equal(t, *dmarc.Record("@").Name, "_dmarc")
+	assert.Equal(t, *dmarc.Record("shop").Name, "_dmarc.shop")
+	assert.Equal(t, *dmarc.Record("@").Value, "v=DMARC1; p=reject")
+}
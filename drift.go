@@ -0,0 +1,177 @@
+package gohetznerdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Saved state of all zones of an account and their records.
+type Snapshot struct {
+	Taken time.Time       `json:"taken"`
+	Zones []*ZoneSnapshot `json:"zones"`
+}
+
+// Zone and its records within a [Snapshot].
+type ZoneSnapshot struct {
+	Zone    *Zone     `json:"zone"`
+	Records []*Record `json:"records"`
+}
+
+// Reads all zones and records from the API, past the cache.
+func TakeSnapshot(dns HetznerDNS) (*Snapshot, error) {
+	dns = dns.WithoutCache()
+	zones, err := dns.GetZoneService().GetAllZones()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{Taken: time.Now().UTC()}
+	for _, zone := range zones {
+		records, err := dns.GetRecordService().GetAllRecords(zone.Id)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot of zone %s: %w", deref(zone.Name), err)
+		}
+		snapshot.Zones = append(snapshot.Zones, &ZoneSnapshot{Zone: zone, Records: records})
+	}
+	return snapshot, nil
+}
+
+// Reads a snapshot written by [Snapshot.Save].
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := new(Snapshot)
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// Writes the snapshot as indented JSON.
+func (snapshot *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'), 0o644)
+}
+
+// Zone difference of a [DriftReport]. Created zones only have After, deleted zones only Before.
+type ZoneChange struct {
+	Action ChangeAction
+	Before *Zone
+	After  *Zone
+}
+
+func (change *ZoneChange) String() string {
+	switch change.Action {
+	case ChangeUpdate:
+		return fmt.Sprintf("update zone %s ttl %d -> %d", deref(change.Before.Name), deref(change.Before.TTL), deref(change.After.TTL))
+	case ChangeCreate:
+		return fmt.Sprintf("create zone %s", deref(change.After.Name))
+	}
+	return fmt.Sprintf("delete zone %s", deref(change.Before.Name))
+}
+
+// Differences between a snapshot and the live state. Changes describe how the snapshot turned
+// into the live state, so records of created or deleted zones are listed as well.
+type DriftReport struct {
+	Zones   []*ZoneChange
+	Records []*RecordChange
+}
+
+// Reports whether anything changed.
+func (report *DriftReport) HasDrift() bool {
+	return len(report.Zones) > 0 || len(report.Records) > 0
+}
+
+func (report *DriftReport) String() string {
+	var lines []string
+	for _, change := range report.Zones {
+		lines = append(lines, change.String())
+	}
+	for _, change := range report.Records {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Compares the live state of the account with the snapshot.
+func DetectDrift(dns HetznerDNS, snapshot *Snapshot) (*DriftReport, error) {
+	if err := validateNotNil("snapshot", snapshot); err != nil {
+		return nil, err
+	}
+	live, err := TakeSnapshot(dns)
+	if err != nil {
+		return nil, err
+	}
+	return CompareSnapshots(snapshot, live), nil
+}
+
+// Returns the changes between two snapshots. Zones and records are matched by id, only names,
+// types, values and TTLs are compared.
+func CompareSnapshots(before, after *Snapshot) *DriftReport {
+	report := &DriftReport{}
+	afterZones := map[string]*ZoneSnapshot{}
+	for _, zone := range after.Zones {
+		afterZones[deref(zone.Zone.Id)] = zone
+	}
+	for _, old := range before.Zones {
+		current, ok := afterZones[deref(old.Zone.Id)]
+		if !ok {
+			report.Zones = append(report.Zones, &ZoneChange{Action: ChangeDelete, Before: old.Zone})
+			report.Records = append(report.Records, compareRecords(old.Records, nil)...)
+			continue
+		}
+		delete(afterZones, deref(old.Zone.Id))
+		if !strings.EqualFold(deref(old.Zone.Name), deref(current.Zone.Name)) || !sameTTL(old.Zone.TTL, current.Zone.TTL) {
+			report.Zones = append(report.Zones, &ZoneChange{Action: ChangeUpdate, Before: old.Zone, After: current.Zone})
+		}
+		report.Records = append(report.Records, compareRecords(old.Records, current.Records)...)
+	}
+	for _, zone := range after.Zones {
+		if _, ok := afterZones[deref(zone.Zone.Id)]; ok {
+			report.Zones = append(report.Zones, &ZoneChange{Action: ChangeCreate, After: zone.Zone})
+			report.Records = append(report.Records, compareRecords(nil, zone.Records)...)
+		}
+	}
+	return report
+}
+
+func compareRecords(before, after []*Record) []*RecordChange {
+	var changes []*RecordChange
+	afterRecords := map[string]*Record{}
+	for _, record := range after {
+		afterRecords[deref(record.Id)] = record
+	}
+	for _, old := range before {
+		current, ok := afterRecords[deref(old.Id)]
+		if !ok {
+			changes = append(changes, &RecordChange{Action: ChangeDelete, Before: old})
+			continue
+		}
+		delete(afterRecords, deref(old.Id))
+		if !sameRecordName(old.Name, current.Name) || !sameRecordType(old.Type, current.Type) ||
+			deref(old.Value) != deref(current.Value) || !sameTTL(old.TTL, current.TTL) {
+			changes = append(changes, &RecordChange{Action: ChangeUpdate, Before: old, After: current})
+		}
+	}
+	var created []*Record
+	for _, record := range after {
+		if _, ok := afterRecords[deref(record.Id)]; ok {
+			created = append(created, record)
+		}
+	}
+	sort.SliceStable(created, func(i, j int) bool {
+		return normalizeRecordName(deref(created[i].Name)) < normalizeRecordName(deref(created[j].Name))
+	})
+	for _, record := range created {
+		changes = append(changes, &RecordChange{Action: ChangeCreate, After: record})
+	}
+	return changes
+}
@@ -0,0 +1,52 @@
+package gohetznerdns
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestDetectDrift(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	other := api.addZone("example.org", 3600)
+	www := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	mail := api.addRecord(*zone.Id, "mail", "A", "192.0.2.2", nil)
+	api.addRecord(*other.Id, "@", "TXT", "hello", nil)
+	dns := api.dns(t)
+
+	snapshot, err := TakeSnapshot(dns)
+	assert.NilError(t, err)
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	assert.NilError(t, snapshot.Save(path))
+	snapshot, err = LoadSnapshot(path)
+	assert.NilError(t, err)
+
+	report, err := DetectDrift(dns, snapshot)
+	assert.NilError(t, err)
+	assert.Assert(t, !report.HasDrift())
+
+	records := dns.GetRecordService()
+	_, err = records.UpdateRecord(&Record{Id: www.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.9")})
+	assert.NilError(t, err)
+	assert.NilError(t, records.DeleteRecord(mail.Id))
+	_, err = records.CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("ftp"), Type: ptr("CNAME"), Value: ptr("www")})
+	assert.NilError(t, err)
+	_, err = dns.GetZoneService().UpdateZone(zone.Id, &ZoneRequest{Name: zone.Name, TTL: ptr(600)})
+	assert.NilError(t, err)
+	assert.NilError(t, dns.GetZoneService().DeleteZone(other.Id))
+	_, err = dns.GetZoneService().CreateZone(&ZoneRequest{Name: ptr("example.net")})
+	assert.NilError(t, err)
+
+	report, err = DetectDrift(dns, snapshot)
+	assert.NilError(t, err)
+	assert.Assert(t, report.HasDrift())
+	assert.Equal(t, report.String(), `update zone example.com ttl 3600 -> 600
+delete zone example.org
+create zone example.net
+update www A 192.0.2.1 -> 192.0.2.9
+delete mail A 192.0.2.2
+create ftp CNAME www
+delete @ TXT hello`)
+}
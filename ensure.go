@@ -0,0 +1,163 @@
+package gohetznerdns
+
+import "strings"
+
+// Outcome of an idempotent record operation.
+//
+// Records are matched by zone, name and type. Names and types are compared
+// case-insensitively, "@" and "" both denote the zone apex and a trailing dot
+// on the name is ignored. Values are compared exactly, except for types whose
+// value is a host name (CNAME, NS, PTR, MX, SRV) where case is ignored.
+//
+// [RecordService.EnsureRecord] keeps the first record matching name, type and
+// value and removes any duplicates of it. When no value matches and the type
+// allows a single record per name (CNAME, SOA) the existing record is updated
+// in place, otherwise a new record is created. A nil ttl accepts whatever TTL
+// the matching record has.
+type EnsureResult string
+
+const (
+	EnsureUnchanged EnsureResult = "unchanged"
+	EnsureCreated   EnsureResult = "created"
+	EnsureUpdated   EnsureResult = "updated"
+	EnsureDeleted   EnsureResult = "deleted"
+)
+
+var singletonRecordTypes = []string{"CNAME", "SOA"}
+
+var hostnameRecordTypes = []string{"CNAME", "NS", "PTR", "MX", "SRV"}
+
+func normalizeRecordName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	return iif(name == "", "@", name)
+}
+
+func normalizeRecordType(recordType string) string {
+	return strings.ToUpper(strings.TrimSpace(recordType))
+}
+
+func sameRecordName(a, b *string) bool {
+	return normalizeRecordName(deref(a)) == normalizeRecordName(deref(b))
+}
+
+func sameRecordType(a, b *string) bool {
+	return strings.EqualFold(deref(a), deref(b))
+}
+
+func sameRecordValue(recordType, a, b *string) bool {
+	if containsFold(hostnameRecordTypes, deref(recordType)) {
+		return strings.EqualFold(deref(a), deref(b))
+	}
+	return deref(a) == deref(b)
+}
+
+func sameTTL(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterRecords(records []*Record, name, recordType *string) []*Record {
+	var result []*Record
+	for _, record := range records {
+		if sameRecordName(record.Name, name) && sameRecordType(record.Type, recordType) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func (service *recordService) EnsureRecord(zone_id, name, record_type, value *string, ttl *int) (*Record, EnsureResult, error) {
+	if err := validateNotEmpty("name", name); err != nil {
+		return nil, EnsureUnchanged, err
+	}
+	if err := validateNotEmpty("record_type", record_type); err != nil {
+		return nil, EnsureUnchanged, err
+	}
+	if err := validateNotNil("value", value); err != nil {
+		return nil, EnsureUnchanged, err
+	}
+	records, err := service.fresh().GetAllRecords(zone_id)
+	if err != nil {
+		return nil, EnsureUnchanged, err
+	}
+	candidates := filterRecords(records, name, record_type)
+
+	var keep *Record
+	var remove []*Record
+	for _, candidate := range candidates {
+		if keep == nil && sameRecordValue(record_type, candidate.Value, value) {
+			keep = candidate
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate == keep {
+			continue
+		}
+		if sameRecordValue(record_type, candidate.Value, value) {
+			remove = append(remove, candidate)
+		}
+	}
+	result := EnsureUnchanged
+	if keep == nil && len(candidates) > 0 && containsFold(singletonRecordTypes, *record_type) {
+		keep = &Record{Id: candidates[0].Id, ZoneId: zone_id, Name: candidates[0].Name, Type: candidates[0].Type, Value: value, TTL: iif(ttl == nil, candidates[0].TTL, ttl)}
+		if keep, err = service.UpdateRecord(keep); err != nil {
+			return nil, EnsureUnchanged, err
+		}
+		result = EnsureUpdated
+		remove = candidates[1:]
+	} else if keep == nil {
+		keep = &Record{ZoneId: zone_id, Name: name, Type: record_type, Value: value, TTL: ttl}
+		if keep, err = service.CreateRecord(keep); err != nil {
+			return nil, EnsureUnchanged, err
+		}
+		result = EnsureCreated
+	} else if ttl != nil && !sameTTL(keep.TTL, ttl) {
+		update := &Record{Id: keep.Id, ZoneId: zone_id, Name: keep.Name, Type: keep.Type, Value: keep.Value, TTL: ttl}
+		if keep, err = service.UpdateRecord(update); err != nil {
+			return nil, EnsureUnchanged, err
+		}
+		result = EnsureUpdated
+	}
+	for _, record := range remove {
+		if err := service.DeleteRecord(record.Id); err != nil {
+			return keep, result, err
+		}
+		result = iif(result == EnsureUnchanged, EnsureUpdated, result)
+	}
+	return keep, result, nil
+}
+
+func (service *recordService) EnsureAbsent(zone_id, name, record_type, value *string) (EnsureResult, error) {
+	if err := validateNotEmpty("name", name); err != nil {
+		return EnsureUnchanged, err
+	}
+	if err := validateNotEmpty("record_type", record_type); err != nil {
+		return EnsureUnchanged, err
+	}
+	records, err := service.fresh().GetAllRecords(zone_id)
+	if err != nil {
+		return EnsureUnchanged, err
+	}
+	result := EnsureUnchanged
+	for _, record := range filterRecords(records, name, record_type) {
+		if value != nil && !sameRecordValue(record_type, record.Value, value) {
+			continue
+		}
+		if err := service.DeleteRecord(record.Id); err != nil {
+			return result, err
+		}
+		result = EnsureDeleted
+	}
+	return result, nil
+}
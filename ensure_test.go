@@ -0,0 +1,113 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestEnsureRecordCreatesThenLeavesUnchanged(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	service := api.dns(t).GetRecordService()
+
+	record, result, err := service.EnsureRecord(zone.Id, ptr("www"), ptr("A"), ptr("192.0.2.1"), ptr(300))
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureCreated)
+	assert.Equal(t, *record.Value, "192.0.2.1")
+
+	record, result, err = service.EnsureRecord(zone.Id, ptr("WWW"), ptr("a"), ptr("192.0.2.1"), ptr(300))
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUnchanged)
+	assert.Equal(t, *record.Name, "www")
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+}
+
+func TestEnsureRecordUpdatesTTL(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	existing := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", ptr(300))
+	service := api.dns(t).GetRecordService()
+
+	record, result, err := service.EnsureRecord(zone.Id, ptr("www"), ptr("A"), ptr("192.0.2.1"), ptr(60))
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUpdated)
+	assert.Equal(t, *record.Id, *existing.Id)
+	assert.Equal(t, *record.TTL, 60)
+
+	_, result, err = service.EnsureRecord(zone.Id, ptr("www"), ptr("A"), ptr("192.0.2.1"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUnchanged)
+}
+
+func TestEnsureRecordAddsToMultiValueSet(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	service := api.dns(t).GetRecordService()
+
+	_, result, err := service.EnsureRecord(zone.Id, ptr("www"), ptr("A"), ptr("192.0.2.2"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureCreated)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 2)
+}
+
+func TestEnsureRecordReplacesCNAME(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	existing := api.addRecord(*zone.Id, "www", "CNAME", "old.example.net.", nil)
+	api.addRecord(*zone.Id, "www", "CNAME", "older.example.net.", nil)
+	service := api.dns(t).GetRecordService()
+
+	record, result, err := service.EnsureRecord(zone.Id, ptr("www"), ptr("CNAME"), ptr("new.example.net."), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUpdated)
+	assert.Equal(t, *record.Id, *existing.Id)
+	records := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, *records[0].Value, "new.example.net.")
+}
+
+func TestEnsureRecordRemovesDuplicates(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "@", "TXT", "v=spf1 -all", nil)
+	api.addRecord(*zone.Id, "", "TXT", "v=spf1 -all", nil)
+	service := api.dns(t).GetRecordService()
+
+	_, result, err := service.EnsureRecord(zone.Id, ptr("@"), ptr("TXT"), ptr("v=spf1 -all"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUpdated)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+}
+
+func TestEnsureRecordValidation(t *testing.T) {
+	service := &recordService{}
+	_, _, err := service.EnsureRecord(ptr("zone"), ptr(" "), ptr("A"), ptr("192.0.2.1"), nil)
+	assert.Error(t, err, "901 : name is empty")
+	_, _, err = service.EnsureRecord(ptr("zone"), ptr("www"), ptr("A"), nil, nil)
+	assert.Error(t, err, "900 : value is nil")
+}
+
+func TestEnsureAbsent(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", nil)
+	api.addRecord(*zone.Id, "www", "AAAA", "2001:db8::1", nil)
+	service := api.dns(t).GetRecordService()
+
+	result, err := service.EnsureAbsent(zone.Id, ptr("www"), ptr("A"), ptr("192.0.2.1"))
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureDeleted)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 2)
+
+	result, err = service.EnsureAbsent(zone.Id, ptr("www"), ptr("A"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureDeleted)
+
+	result, err = service.EnsureAbsent(zone.Id, ptr("www"), ptr("A"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUnchanged)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+}
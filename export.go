@@ -0,0 +1,351 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Records of one name and type prepared for export. TTL is nil when all records inherit the
+// zone default, otherwise the lowest explicit TTL since targets keep one TTL per RRset.
+type exportRRSet struct {
+	name       string
+	recordType string
+	ttl        *int
+	records    []*Record
+}
+
+// Field of a structured record value, e.g. the preference of an MX record. Scalar values
+// consist of a single field without key.
+type exportField struct {
+	key    string
+	value  string
+	number bool
+}
+
+// Groups the records into RRsets sorted by name and type, leaving out the SOA and apex NS
+// records maintained by Hetzner.
+func exportRRSets(records []*Record) []*exportRRSet {
+	var unmanaged []*Record
+	for _, record := range records {
+		if !isManagedRecord(record) {
+			unmanaged = append(unmanaged, record)
+		}
+	}
+	groups, keys := groupRRSets(unmanaged)
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name == "@" || (keys[j].name != "@" && keys[i].name < keys[j].name)
+		}
+		return keys[i].recordType < keys[j].recordType
+	})
+	var rrsets []*exportRRSet
+	for _, key := range keys {
+		rrset := &exportRRSet{name: key.name, recordType: key.recordType, records: groups[key]}
+		for _, record := range rrset.records {
+			if record.TTL != nil && (rrset.ttl == nil || *record.TTL < *rrset.ttl) {
+				rrset.ttl = record.TTL
+			}
+		}
+		rrsets = append(rrsets, rrset)
+	}
+	return rrsets
+}
+
+// Turns a name relative to the zone into a fully qualified name with trailing dot.
+func qualifyName(name, zoneName string) string {
+	zoneName = strings.TrimSuffix(zoneName, ".")
+	switch {
+	case strings.HasSuffix(name, "."):
+		return name
+	case name == "@" || name == "":
+		return zoneName + "."
+	}
+	return name + "." + zoneName + "."
+}
+
+// Splits a record value into its fields. Hostnames are qualified since the export targets
+// expect absolute names.
+func exportRecordFields(zoneName, recordType, value string) ([]*exportField, error) {
+	fields := strings.Fields(value)
+	// The first numeric fields are numbers, the rest strings.
+	structured := func(numeric int, keys ...string) ([]*exportField, error) {
+		if len(fields) != len(keys) {
+			return nil, fmt.Errorf("export: invalid %s value %q", recordType, value)
+		}
+		result := make([]*exportField, len(keys))
+		for i, key := range keys {
+			result[i] = &exportField{key: key, value: fields[i], number: i < numeric}
+			if _, err := strconv.Atoi(fields[i]); i < numeric && err != nil {
+				return nil, fmt.Errorf("export: invalid %s value %q", recordType, value)
+			}
+		}
+		return result, nil
+	}
+	switch recordType {
+	case "A", "AAAA", "TXT":
+		return []*exportField{{value: value}}, nil
+	case "CNAME", "NS", "PTR":
+		return []*exportField{{value: qualifyName(value, zoneName)}}, nil
+	case "MX":
+		result, err := structured(1, "preference", "exchange")
+		if err == nil {
+			result[1].value = qualifyName(result[1].value, zoneName)
+		}
+		return result, err
+	case "SRV":
+		result, err := structured(3, "priority", "weight", "port", "target")
+		if err == nil {
+			result[3].value = qualifyName(result[3].value, zoneName)
+		}
+		return result, err
+	case "CAA":
+		if len(fields) > 3 {
+			fields = append(fields[:2], strings.Join(fields[2:], " "))
+		}
+		result, err := structured(1, "flags", "tag", "value")
+		if err == nil {
+			result[2].value = unquoteTXT(result[2].value)
+		}
+		return result, err
+	case "TLSA":
+		if len(fields) > 4 {
+			fields = append(fields[:3], strings.Join(fields[3:], ""))
+		}
+		return structured(3, "certificate_usage", "selector", "matching_type", "certificate_association_data")
+	case "DS":
+		if len(fields) > 4 {
+			fields = append(fields[:3], strings.Join(fields[3:], ""))
+		}
+		return structured(3, "key_tag", "algorithm", "digest_type", "digest")
+	}
+	return nil, fmt.Errorf("export: %s records are not supported", recordType)
+}
+
+func yamlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func yamlField(field *exportField) string {
+	if field.number {
+		return field.value
+	}
+	return yamlQuote(field.value)
+}
+
+// Exports the records of the zone as an OctoDNS YAML zone file. Records inheriting the zone
+// TTL get it set explicitly since OctoDNS has no zone default.
+func ExportOctoDNS(zone *Zone, records []*Record) (string, error) {
+	if err := validateNotEmpty("zone.Name", zone.Name); err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	builder.WriteString("---\n")
+	current := ""
+	for i, rrset := range exportRRSets(records) {
+		if i == 0 || rrset.name != current {
+			current = rrset.name
+			builder.WriteString(yamlQuote(iif(rrset.name == "@", "", rrset.name)) + ":\n")
+		}
+		ttl := iif(rrset.ttl != nil, rrset.ttl, zone.TTL)
+		if ttl != nil {
+			fmt.Fprintf(&builder, "- ttl: %d\n  type: %s\n", *ttl, rrset.recordType)
+		} else {
+			fmt.Fprintf(&builder, "- type: %s\n", rrset.recordType)
+		}
+		if rrset.recordType == "CNAME" {
+			fields, err := exportRecordFields(*zone.Name, rrset.recordType, deref(rrset.records[0].Value))
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString("  value: " + yamlField(fields[0]) + "\n")
+			continue
+		}
+		builder.WriteString("  values:\n")
+		for _, record := range rrset.records {
+			fields, err := exportRecordFields(*zone.Name, rrset.recordType, deref(record.Value))
+			if err != nil {
+				return "", err
+			}
+			if len(fields) == 1 {
+				value := fields[0].value
+				if rrset.recordType == "TXT" {
+					value = strings.ReplaceAll(value, ";", `\;`)
+				}
+				builder.WriteString("  - " + yamlQuote(value) + "\n")
+				continue
+			}
+			for j, field := range fields {
+				fmt.Fprintf(&builder, "  %s %s: %s\n", iif(j == 0, "-", " "), field.key, yamlField(field))
+			}
+		}
+	}
+	return builder.String(), nil
+}
+
+// Exports the zone as a DNSControl dnsconfig.js domain. The zone TTL becomes DefaultTTL and
+// only records with their own TTL get a TTL modifier.
+func ExportDNSControl(zone *Zone, records []*Record) (string, error) {
+	if err := validateNotEmpty("zone.Name", zone.Name); err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	builder.WriteString("var REG_NONE = NewRegistrar(\"none\");\n")
+	builder.WriteString("var DSP_HETZNER = NewDnsProvider(\"hetzner\");\n\n")
+	fmt.Fprintf(&builder, "D(%s, REG_NONE, DnsProvider(DSP_HETZNER)", strconv.Quote(*zone.Name))
+	if zone.TTL != nil {
+		fmt.Fprintf(&builder, ",\n\tDefaultTTL(%d)", *zone.TTL)
+	}
+	for _, rrset := range exportRRSets(records) {
+		for _, record := range rrset.records {
+			fields, err := exportRecordFields(*zone.Name, rrset.recordType, deref(record.Value))
+			if err != nil {
+				return "", err
+			}
+			args := []string{strconv.Quote(rrset.name)}
+			if rrset.recordType == "CAA" {
+				args = append(args, strconv.Quote(fields[1].value), strconv.Quote(fields[2].value))
+				if fields[0].value == "128" {
+					args = append(args, "CAA_CRITICAL")
+				}
+			} else {
+				for _, field := range fields {
+					args = append(args, iif(field.number, field.value, strconv.Quote(field.value)))
+				}
+			}
+			if rrset.ttl != nil {
+				args = append(args, fmt.Sprintf("TTL(%d)", *rrset.ttl))
+			}
+			fmt.Fprintf(&builder, ",\n\t%s(%s)", rrset.recordType, strings.Join(args, ", "))
+		}
+	}
+	builder.WriteString("\n);\n")
+	return builder.String(), nil
+}
+
+// Terraform configuration of a zone together with the commands importing the existing
+// zone and records into the Terraform state.
+type TerraformExport struct {
+	Config  string
+	Imports []string
+}
+
+var terraformInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Resource names handed out within one export.
+type terraformNames map[string]bool
+
+// Returns a resource name made of the parts that is unique within the export. "@" becomes
+// apex and "*" wildcard, other characters invalid in identifiers become "_". Names that still
+// collide, e.g. "a.b" and "a_b", get a numeric suffix.
+func (names terraformNames) identifier(parts ...string) string {
+	for i, part := range parts {
+		part = iif(part == "@", "apex", strings.ReplaceAll(part, "*", "wildcard"))
+		parts[i] = terraformInvalidChars.ReplaceAllString(part, "_")
+	}
+	identifier := strings.ToLower(strings.Join(parts, "_"))
+	if identifier == "" || !(identifier[0] == '_' || (identifier[0] >= 'a' && identifier[0] <= 'z')) {
+		identifier = "_" + identifier
+	}
+	unique := identifier
+	for n := 2; names[unique]; n++ {
+		unique = fmt.Sprintf("%s_%d", identifier, n)
+	}
+	names[unique] = true
+	return unique
+}
+
+func terraformQuote(value string) string {
+	quoted := strconv.Quote(value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+// Exports the zone as Terraform configuration for the hetznerdns provider, which manages each
+// record as its own resource. Records of an RRset get numbered resource names and records
+// inheriting the zone TTL leave ttl unset. Import commands are only returned for the zone and
+// records with ids. See [ExportTerraformHCloud] for the hcloud provider.
+func ExportTerraform(zone *Zone, records []*Record) (*TerraformExport, error) {
+	if err := validateNotEmpty("zone.Name", zone.Name); err != nil {
+		return nil, err
+	}
+	export := &TerraformExport{}
+	var builder strings.Builder
+	names := terraformNames{}
+	zoneResource := names.identifier(*zone.Name)
+	fmt.Fprintf(&builder, "resource \"hetznerdns_zone\" %s {\n  name = %s\n", strconv.Quote(zoneResource), terraformQuote(*zone.Name))
+	if zone.TTL != nil {
+		fmt.Fprintf(&builder, "  ttl  = %d\n", *zone.TTL)
+	}
+	builder.WriteString("}\n")
+	if zone.Id != nil {
+		export.Imports = append(export.Imports, fmt.Sprintf("terraform import hetznerdns_zone.%s %s", zoneResource, *zone.Id))
+	}
+	for _, rrset := range exportRRSets(records) {
+		for i, record := range rrset.records {
+			parts := []string{*zone.Name, rrset.name, rrset.recordType}
+			if len(rrset.records) > 1 {
+				parts = append(parts, strconv.Itoa(i+1))
+			}
+			resource := names.identifier(parts...)
+			fmt.Fprintf(&builder, "\nresource \"hetznerdns_record\" %s {\n", strconv.Quote(resource))
+			fmt.Fprintf(&builder, "  zone_id = hetznerdns_zone.%s.id\n", zoneResource)
+			fmt.Fprintf(&builder, "  name    = %s\n", terraformQuote(rrset.name))
+			fmt.Fprintf(&builder, "  type    = %s\n", terraformQuote(rrset.recordType))
+			fmt.Fprintf(&builder, "  value   = %s\n", terraformQuote(deref(record.Value)))
+			if record.TTL != nil {
+				fmt.Fprintf(&builder, "  ttl     = %d\n", *record.TTL)
+			}
+			builder.WriteString("}\n")
+			if record.Id != nil {
+				export.Imports = append(export.Imports, fmt.Sprintf("terraform import hetznerdns_record.%s %s", resource, *record.Id))
+			}
+		}
+	}
+	export.Config = builder.String()
+	return export, nil
+}
+
+// Exports the zone as Terraform configuration for the hcloud provider, which manages the zones
+// of the Hetzner Console as hcloud_zone and each RRset as one hcloud_zone_rrset. TXT values are
+// quoted as the Cloud API expects. Zones are imported by name and RRsets by zone/name/type,
+// since zone ids differ between the DNS Console and the Cloud API.
+func ExportTerraformHCloud(zone *Zone, records []*Record) (*TerraformExport, error) {
+	if err := validateNotEmpty("zone.Name", zone.Name); err != nil {
+		return nil, err
+	}
+	export := &TerraformExport{}
+	var builder strings.Builder
+	names := terraformNames{}
+	zoneResource := names.identifier(*zone.Name)
+	fmt.Fprintf(&builder, "resource \"hcloud_zone\" %s {\n  name = %s\n  mode = \"primary\"\n", strconv.Quote(zoneResource), terraformQuote(*zone.Name))
+	if zone.TTL != nil {
+		fmt.Fprintf(&builder, "  ttl  = %d\n", *zone.TTL)
+	}
+	builder.WriteString("}\n")
+	export.Imports = append(export.Imports, fmt.Sprintf("terraform import hcloud_zone.%s %s", zoneResource, *zone.Name))
+	for _, rrset := range exportRRSets(records) {
+		resource := names.identifier(*zone.Name, rrset.name, rrset.recordType)
+		fmt.Fprintf(&builder, "\nresource \"hcloud_zone_rrset\" %s {\n", strconv.Quote(resource))
+		fmt.Fprintf(&builder, "  zone = hcloud_zone.%s.name\n", zoneResource)
+		fmt.Fprintf(&builder, "  name = %s\n", terraformQuote(rrset.name))
+		fmt.Fprintf(&builder, "  type = %s\n", terraformQuote(rrset.recordType))
+		if rrset.ttl != nil {
+			fmt.Fprintf(&builder, "  ttl  = %d\n", *rrset.ttl)
+		}
+		builder.WriteString("  records = [\n")
+		for _, record := range rrset.records {
+			value := deref(record.Value)
+			if rrset.recordType == "TXT" {
+				value = quoteTXT(unquoteTXT(value))
+			}
+			fmt.Fprintf(&builder, "    { value = %s },\n", terraformQuote(value))
+		}
+		builder.WriteString("  ]\n}\n")
+		export.Imports = append(export.Imports, fmt.Sprintf("terraform import hcloud_zone_rrset.%s %s/%s/%s", resource, *zone.Name, rrset.name, rrset.recordType))
+	}
+	export.Config = builder.String()
+	return export, nil
+}
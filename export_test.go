@@ -0,0 +1,201 @@
+package gohetznerdns
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func exportTestZone() (*Zone, []*Record) {
+	zone := &Zone{Id: ptr("z1"), Name: ptr("example.com"), TTL: ptr(3600)}
+	record := func(id, name, recordType, value string, ttl *int) *Record {
+		return &Record{Id: ptr(id), ZoneId: zone.Id, Name: ptr(name), Type: ptr(recordType), Value: ptr(value), TTL: ttl}
+	}
+	return zone, []*Record{
+		record("r1", "@", "SOA", "hydrogen.ns.hetzner.com. dns.hetzner.com. 1 86400 10800 3600000 3600", nil),
+		record("r2", "@", "NS", "hydrogen.ns.hetzner.com.", nil),
+		record("r3", "www", "A", "192.0.2.1", ptr(300)),
+		record("r4", "www", "A", "192.0.2.2", ptr(300)),
+		record("r5", "@", "MX", "10 mail", nil),
+		record("r6", "@", "TXT", "v=spf1 mx; -all", nil),
+		record("r7", "ftp", "CNAME", "www", nil),
+		record("r8", "@", "CAA", `0 issue "letsencrypt.org"`, nil),
+		record("r9", "_sip._tcp", "SRV", "10 5 5060 sip.example.net.", ptr(60)),
+	}
+}
+
+func TestExportOctoDNS(t *testing.T) {
+	zone, records := exportTestZone()
+	yaml, err := ExportOctoDNS(zone, records)
+	assert.NilError(t, err)
+	assert.Equal(t, yaml, `---
+'':
+- ttl: 3600
+  type: CAA
+  values:
+  - flags: 0
+    tag: 'issue'
+    value: 'letsencrypt.org'
+- ttl: 3600
+  type: MX
+  values:
+  - preference: 10
+    exchange: 'mail.example.com.'
+- ttl: 3600
+  type: TXT
+  values:
+  - 'v=spf1 mx\; -all'
+'_sip._tcp':
+- ttl: 60
+  type: SRV
+  values:
+  - priority: 10
+    weight: 5
+    port: 5060
+    target: 'sip.example.net.'
+'ftp':
+- ttl: 3600
+  type: CNAME
+  value: 'www.example.com.'
+'www':
+- ttl: 300
+  type: A
+  values:
+  - '192.0.2.1'
+  - '192.0.2.2'
+`)
+}
+
+func TestExportDNSControl(t *testing.T) {
+	zone, records := exportTestZone()
+	js, err := ExportDNSControl(zone, records)
+	assert.NilError(t, err)
+	assert.Equal(t, js, `var REG_NONE = NewRegistrar("none");
+var DSP_HETZNER = NewDnsProvider("hetzner");
+
+D("example.com", REG_NONE, DnsProvider(DSP_HETZNER),
+	DefaultTTL(3600),
+	CAA("@", "issue", "letsencrypt.org"),
+	MX("@", 10, "mail.example.com."),
+	TXT("@", "v=spf1 mx; -all"),
+	SRV("_sip._tcp", 10, 5, 5060, "sip.example.net.", TTL(60)),
+	CNAME("ftp", "www.example.com."),
+	A("www", "192.0.2.1", TTL(300)),
+	A("www", "192.0.2.2", TTL(300))
+);
+`)
+}
+
+func TestExportTerraform(t *testing.T) {
+	zone, records := exportTestZone()
+	export, err := ExportTerraform(zone, records[:5])
+	assert.NilError(t, err)
+	assert.Equal(t, export.Config, `resource "hetznerdns_zone" "example_com" {
+  name = "example.com"
+  ttl  = 3600
+}
+
+resource "hetznerdns_record" "example_com_apex_mx" {
+  zone_id = hetznerdns_zone.example_com.id
+  name    = "@"
+  type    = "MX"
+  value   = "10 mail"
+}
+
+resource "hetznerdns_record" "example_com_www_a_1" {
+  zone_id = hetznerdns_zone.example_com.id
+  name    = "www"
+  type    = "A"
+  value   = "192.0.2.1"
+  ttl     = 300
+}
+
+resource "hetznerdns_record" "example_com_www_a_2" {
+  zone_id = hetznerdns_zone.example_com.id
+  name    = "www"
+  type    = "A"
+  value   = "192.0.2.2"
+  ttl     = 300
+}
+`)
+	assert.DeepEqual(t, export.Imports, []string{
+		"terraform import hetznerdns_zone.example_com z1",
+		"terraform import hetznerdns_record.example_com_apex_mx r5",
+		"terraform import hetznerdns_record.example_com_www_a_1 r3",
+		"terraform import hetznerdns_record.example_com_www_a_2 r4",
+	})
+}
+
+func TestExportTerraformUniqueNames(t *testing.T) {
+	zone := &Zone{Name: ptr("example.com")}
+	export, err := ExportTerraform(zone, []*Record{
+		{Name: ptr("_dmarc"), Type: ptr("TXT"), Value: ptr("v=DMARC1; p=none")},
+		{Name: ptr("dmarc"), Type: ptr("TXT"), Value: ptr("x")},
+		{Name: ptr("*"), Type: ptr("A"), Value: ptr("192.0.2.1")},
+		{Name: ptr("*.www"), Type: ptr("A"), Value: ptr("192.0.2.2")},
+		{Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.3")},
+		{Name: ptr("a.b"), Type: ptr("A"), Value: ptr("192.0.2.4")},
+		{Name: ptr("a_b"), Type: ptr("A"), Value: ptr("192.0.2.5")},
+	})
+	assert.NilError(t, err)
+	var resources []string
+	for _, line := range strings.Split(export.Config, "\n") {
+		if strings.HasPrefix(line, "resource ") {
+			resources = append(resources, strings.Fields(line)[2])
+		}
+	}
+	assert.DeepEqual(t, resources, []string{
+		`"example_com"`,
+		`"example_com_wildcard_a"`,
+		`"example_com_wildcard_www_a"`,
+		`"example_com__dmarc_txt"`,
+		`"example_com_a_b_a"`,
+		`"example_com_a_b_a_2"`,
+		`"example_com_dmarc_txt"`,
+		`"example_com_www_a"`,
+	})
+}
+
+func TestExportTerraformHCloud(t *testing.T) {
+	zone, records := exportTestZone()
+	export, err := ExportTerraformHCloud(zone, []*Record{records[2], records[3], records[5]})
+	assert.NilError(t, err)
+	assert.Equal(t, export.Config, `resource "hcloud_zone" "example_com" {
+  name = "example.com"
+  mode = "primary"
+  ttl  = 3600
+}
+
+resource "hcloud_zone_rrset" "example_com_apex_txt" {
+  zone = hcloud_zone.example_com.name
+  name = "@"
+  type = "TXT"
+  records = [
+    { value = "\"v=spf1 mx; -all\"" },
+  ]
+}
+
+resource "hcloud_zone_rrset" "example_com_www_a" {
+  zone = hcloud_zone.example_com.name
+  name = "www"
+  type = "A"
+  ttl  = 300
+  records = [
+    { value = "192.0.2.1" },
+    { value = "192.0.2.2" },
+  ]
+}
+`)
+	assert.DeepEqual(t, export.Imports, []string{
+		"terraform import hcloud_zone.example_com example.com",
+		"terraform import hcloud_zone_rrset.example_com_apex_txt example.com/@/TXT",
+		"terraform import hcloud_zone_rrset.example_com_www_a example.com/www/A",
+	})
+}
+
+func TestExportUnsupportedType(t *testing.T) {
+	zone := &Zone{Name: ptr("example.com")}
+	_, err := ExportOctoDNS(zone, []*Record{{Name: ptr("@"), Type: ptr("HINFO"), Value: ptr(`"x86" "linux"`)}})
+	assert.ErrorContains(t, err, "HINFO records are not supported")
+}
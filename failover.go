@@ -0,0 +1,269 @@
+package gohetznerdns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health check of a failover endpoint. A nil error means healthy.
+type Probe interface {
+	Check(ctx context.Context) error
+}
+
+// Adapts a function to a [Probe].
+type ProbeFunc func(ctx context.Context) error
+
+func (probe ProbeFunc) Check(ctx context.Context) error {
+	return probe(ctx)
+}
+
+// Healthy when a GET of URL answers with ExpectStatus, or any 2xx or 3xx status when unset.
+type HTTPProbe struct {
+	URL          string
+	ExpectStatus int
+	// Client used for the request, defaults to a client not following redirects.
+	Client *http.Client
+}
+
+var probeHTTPClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+func (probe *HTTPProbe) Check(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return err
+	}
+	response, err := iif(probe.Client != nil, probe.Client, probeHTTPClient).Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if (probe.ExpectStatus != 0 && response.StatusCode != probe.ExpectStatus) ||
+		(probe.ExpectStatus == 0 && (response.StatusCode < 200 || response.StatusCode >= 400)) {
+		return fmt.Errorf("probe: %s answered %d", probe.URL, response.StatusCode)
+	}
+	return nil
+}
+
+// Healthy when a TCP connection to Address can be established.
+type TCPProbe struct {
+	Address string
+}
+
+func (probe *TCPProbe) Check(ctx context.Context) error {
+	var dialer net.Dialer
+	connection, err := dialer.DialContext(ctx, "tcp", probe.Address)
+	if err != nil {
+		return err
+	}
+	return connection.Close()
+}
+
+// Endpoint of a failover pair. Address is the record value pointing at it.
+type FailoverEndpoint struct {
+	Address string
+	Probe   Probe
+}
+
+// Kind of a [FailoverEvent].
+type FailoverEventType string
+
+const (
+	// The primary failed FailThreshold checks in a row.
+	FailoverPrimaryUnhealthy FailoverEventType = "PrimaryUnhealthy"
+	// The primary passed RecoverThreshold checks in a row after being unhealthy.
+	FailoverPrimaryHealthy FailoverEventType = "PrimaryHealthy"
+	// The record was switched to the active endpoint.
+	FailoverSwitched FailoverEventType = "Switched"
+	// The switch was not possible: the standby is unhealthy or the record update failed.
+	FailoverError FailoverEventType = "Error"
+)
+
+// State change observed by a [FailoverController].
+type FailoverEvent struct {
+	Type   FailoverEventType
+	Time   time.Time
+	Active string
+	Err    error
+}
+
+// Snapshot of the controller state.
+type FailoverStatus struct {
+	// Address the record points at, empty before the first check.
+	Active               string
+	PrimaryHealthy       bool
+	ConsecutiveFailures  int
+	ConsecutiveSuccesses int
+	LastSwitch           time.Time
+}
+
+// Points a record at the standby endpoint while the primary is unhealthy. The primary is
+// declared unhealthy after FailThreshold failed checks and healthy again after
+// RecoverThreshold passed checks, so a flapping primary does not move the record back and
+// forth. The record is only switched to a standby that passes its own check.
+type FailoverController struct {
+	dns        HetznerDNS
+	zoneId     *string
+	name       *string
+	recordType *string
+	primary    *FailoverEndpoint
+	standby    *FailoverEndpoint
+
+	// Consecutive failed checks before failing over, defaults to 3.
+	FailThreshold int
+	// Consecutive passed checks before failing back, defaults to 5.
+	RecoverThreshold int
+	// Timeout of a single probe, defaults to 5 seconds.
+	Timeout time.Duration
+	// TTL of the record, nil keeps the zone default.
+	TTL *int
+
+	mu     sync.Mutex
+	status FailoverStatus
+	now    func() time.Time
+}
+
+// Creates a controller for the record of the given name and type, e.g. A or AAAA.
+func NewFailoverController(dns HetznerDNS, zoneId, name, recordType *string, primary, standby *FailoverEndpoint) *FailoverController {
+	return &FailoverController{
+		dns:              dns.WithoutCache(),
+		zoneId:           zoneId,
+		name:             name,
+		recordType:       recordType,
+		primary:          primary,
+		standby:          standby,
+		FailThreshold:    3,
+		RecoverThreshold: 5,
+		Timeout:          5 * time.Second,
+		status:           FailoverStatus{PrimaryHealthy: true},
+		now:              time.Now,
+	}
+}
+
+// Returns the current state.
+func (controller *FailoverController) Status() FailoverStatus {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+	return controller.status
+}
+
+func (controller *FailoverController) probe(ctx context.Context, endpoint *FailoverEndpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, controller.Timeout)
+	defer cancel()
+	return endpoint.Probe.Check(ctx)
+}
+
+// Probes the endpoints once, switches the record when needed and returns the events.
+func (controller *FailoverController) Check(ctx context.Context) []*FailoverEvent {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+	status := &controller.status
+	var events []*FailoverEvent
+	event := func(eventType FailoverEventType, err error) {
+		events = append(events, &FailoverEvent{Type: eventType, Time: controller.now(), Active: status.Active, Err: err})
+	}
+
+	if status.Active == "" {
+		rrset, err := controller.dns.GetRecordService().GetRRSet(controller.zoneId, controller.name, controller.recordType)
+		if err != nil {
+			event(FailoverError, err)
+			return events
+		}
+		if len(rrset.Records) == 1 {
+			status.Active = deref(rrset.Records[0].Value)
+		}
+		// After a restart during an outage failing back still needs RecoverThreshold passed checks.
+		if status.Active == controller.standby.Address {
+			status.PrimaryHealthy = false
+		}
+	}
+
+	primaryErr := controller.probe(ctx, controller.primary)
+	if primaryErr != nil {
+		status.ConsecutiveFailures++
+		status.ConsecutiveSuccesses = 0
+	} else {
+		status.ConsecutiveSuccesses++
+		status.ConsecutiveFailures = 0
+	}
+	switch {
+	case status.PrimaryHealthy && status.ConsecutiveFailures >= controller.FailThreshold:
+		status.PrimaryHealthy = false
+		event(FailoverPrimaryUnhealthy, nil)
+	case !status.PrimaryHealthy && status.ConsecutiveSuccesses >= controller.RecoverThreshold:
+		status.PrimaryHealthy = true
+		event(FailoverPrimaryHealthy, nil)
+	}
+
+	target := iif(status.PrimaryHealthy, controller.primary, controller.standby)
+	if target.Address == status.Active || (target == controller.primary && primaryErr != nil) {
+		return events
+	}
+	if target == controller.standby {
+		if err := controller.probe(ctx, controller.standby); err != nil {
+			event(FailoverError, fmt.Errorf("failover: standby %s is unhealthy: %w", target.Address, err))
+			return events
+		}
+	}
+	if err := controller.point(target.Address); err != nil {
+		event(FailoverError, err)
+		return events
+	}
+	status.Active = target.Address
+	status.LastSwitch = controller.now()
+	event(FailoverSwitched, nil)
+	return events
+}
+
+// Points the record at the address, updating the record in place when there is exactly one.
+func (controller *FailoverController) point(address string) error {
+	records := controller.dns.GetRecordService()
+	rrset, err := records.GetRRSet(controller.zoneId, controller.name, controller.recordType)
+	if err != nil {
+		return err
+	}
+	if len(rrset.Records) != 1 {
+		_, err := records.ReplaceRRSet(controller.zoneId, controller.name, controller.recordType, []string{address}, controller.TTL)
+		return err
+	}
+	update := copyRecord(rrset.Records[0], true)
+	update.Value = &address
+	update.TTL = iif(controller.TTL != nil, controller.TTL, update.TTL)
+	_, err = records.UpdateRecord(update)
+	return err
+}
+
+// Interval of [FailoverController.Run] when called with a non-positive interval.
+const defaultFailoverInterval = 10 * time.Second
+
+// Checks at every interval until the context is done and sends the events on the returned
+// channel, which is closed when the controller stops. A non-positive interval checks every
+// 10 seconds.
+func (controller *FailoverController) Run(ctx context.Context, interval time.Duration) <-chan *FailoverEvent {
+	events := make(chan *FailoverEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(iif(interval > 0, interval, defaultFailoverInterval))
+		defer ticker.Stop()
+		for {
+			for _, event := range controller.Check(ctx) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
@@ -0,0 +1,166 @@
+package gohetznerdns
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestFailoverController(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+
+	var primaryUp atomic.Bool
+	primaryUp.Store(true)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !primaryUp.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer primary.Close()
+	standby, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer standby.Close()
+	go func() {
+		for {
+			connection, err := standby.Accept()
+			if err != nil {
+				return
+			}
+			connection.Close()
+		}
+	}()
+
+	controller := NewFailoverController(api.dns(t), zone.Id, ptr("www"), ptr("A"),
+		&FailoverEndpoint{Address: "192.0.2.1", Probe: &HTTPProbe{URL: primary.URL}},
+		&FailoverEndpoint{Address: "192.0.2.2", Probe: &TCPProbe{Address: standby.Addr().String()}})
+	controller.FailThreshold = 2
+	controller.RecoverThreshold = 3
+	ctx := context.Background()
+	check := func() []FailoverEventType {
+		var types []FailoverEventType
+		for _, event := range controller.Check(ctx) {
+			types = append(types, event.Type)
+		}
+		return types
+	}
+	value := func() string {
+		return recordValues(api.zoneRecords(*zone.Id), "www", "A")[0]
+	}
+
+	assert.Equal(t, len(check()), 0)
+	assert.Equal(t, controller.Status().Active, "192.0.2.1")
+
+	primaryUp.Store(false)
+	assert.Equal(t, len(check()), 0)
+	assert.DeepEqual(t, check(), []FailoverEventType{FailoverPrimaryUnhealthy, FailoverSwitched})
+	assert.Equal(t, value(), "192.0.2.2")
+	assert.Equal(t, controller.Status().Active, "192.0.2.2")
+
+	// A single passing check does not fail back.
+	primaryUp.Store(true)
+	check()
+	primaryUp.Store(false)
+	check()
+	primaryUp.Store(true)
+	assert.Equal(t, len(check()), 0)
+	assert.Equal(t, len(check()), 0)
+	assert.DeepEqual(t, check(), []FailoverEventType{FailoverPrimaryHealthy, FailoverSwitched})
+	assert.Equal(t, value(), "192.0.2.1")
+	status := controller.Status()
+	assert.Assert(t, status.PrimaryHealthy)
+	assert.Equal(t, status.ConsecutiveSuccesses, 3)
+}
+
+func TestFailoverKeepsPrimaryWhenStandbyIsDown(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	down := ProbeFunc(func(ctx context.Context) error { return context.DeadlineExceeded })
+	controller := NewFailoverController(api.dns(t), zone.Id, ptr("www"), ptr("A"),
+		&FailoverEndpoint{Address: "192.0.2.1", Probe: down},
+		&FailoverEndpoint{Address: "192.0.2.2", Probe: &TCPProbe{Address: "127.0.0.1:1"}})
+	controller.FailThreshold = 1
+
+	events := controller.Check(context.Background())
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[1].Type, FailoverError)
+	assert.ErrorContains(t, events[1].Err, "standby 192.0.2.2 is unhealthy")
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "www", "A"), []string{"192.0.2.1"})
+}
+
+func TestFailoverRestartDuringOutage(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", nil)
+	var primaryUp atomic.Bool
+	primary := ProbeFunc(func(ctx context.Context) error {
+		if !primaryUp.Load() {
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	up := ProbeFunc(func(ctx context.Context) error { return nil })
+	controller := NewFailoverController(api.dns(t), zone.Id, ptr("www"), ptr("A"),
+		&FailoverEndpoint{Address: "192.0.2.1", Probe: primary},
+		&FailoverEndpoint{Address: "192.0.2.2", Probe: up})
+	controller.RecoverThreshold = 2
+
+	assert.Equal(t, len(controller.Check(context.Background())), 0)
+	status := controller.Status()
+	assert.Equal(t, status.Active, "192.0.2.2")
+	assert.Assert(t, !status.PrimaryHealthy)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "www", "A"), []string{"192.0.2.2"})
+
+	primaryUp.Store(true)
+	assert.Equal(t, len(controller.Check(context.Background())), 0)
+	events := controller.Check(context.Background())
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[1].Type, FailoverSwitched)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "www", "A"), []string{"192.0.2.1"})
+}
+
+func TestFailoverRun(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	up := ProbeFunc(func(ctx context.Context) error { return nil })
+	down := ProbeFunc(func(ctx context.Context) error { return context.DeadlineExceeded })
+	controller := NewFailoverController(api.dns(t), zone.Id, ptr("www"), ptr("A"),
+		&FailoverEndpoint{Address: "192.0.2.1", Probe: down},
+		&FailoverEndpoint{Address: "192.0.2.2", Probe: up})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := controller.Run(ctx, time.Millisecond)
+	assert.Equal(t, (<-events).Type, FailoverPrimaryUnhealthy)
+	switched := <-events
+	assert.Equal(t, switched.Type, FailoverSwitched)
+	assert.Equal(t, switched.Active, "192.0.2.2")
+}
+
+func TestFailoverRunWithoutInterval(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	down := ProbeFunc(func(ctx context.Context) error { return context.DeadlineExceeded })
+	up := ProbeFunc(func(ctx context.Context) error { return nil })
+	controller := NewFailoverController(api.dns(t), zone.Id, ptr("www"), ptr("A"),
+		&FailoverEndpoint{Address: "192.0.2.1", Probe: down},
+		&FailoverEndpoint{Address: "192.0.2.2", Probe: up})
+	controller.FailThreshold = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := controller.Run(ctx, 0)
+	assert.Equal(t, (<-events).Type, FailoverPrimaryUnhealthy)
+	cancel()
+	for range events {
+	}
+}
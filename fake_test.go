@@ -0,0 +1,269 @@
+package gohetznerdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAPI is an in-memory implementation of the Hetzner DNS zones and
+// records endpoints used by tests that exercise multi-call workflows.
+type fakeAPI struct {
+	mu      sync.Mutex
+	server  *httptest.Server
+	zones   map[string]*Zone
+	records map[string]*Record
+	nextId  int
+	clock   int
+	calls   []string
+	failOn  func(method, path string, body []byte) bool
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	api := &fakeAPI{
+		zones:   map[string]*Zone{},
+		records: map[string]*Record{},
+	}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (api *fakeAPI) dns(t *testing.T) HetznerDNS {
+	dns, err := NewClient("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dns.SetBaseURL(api.server.URL); err != nil {
+		t.Fatal(err)
+	}
+	return dns
+}
+
+func (api *fakeAPI) id() string {
+	api.nextId++
+	return fmt.Sprintf("id%d", api.nextId)
+}
+
+func (api *fakeAPI) addZone(name string, ttl int) *Zone {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	id := api.id()
+	zone := &Zone{Id: &id, Name: &name, TTL: &ttl, NumberOfRecords: new(int)}
+	api.zones[id] = zone
+	api.touch(id)
+	return zone
+}
+
+func (api *fakeAPI) addRecord(zoneId, name, recordType, value string, ttl *int) *Record {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	id := api.id()
+	record := &Record{Id: &id, ZoneId: &zoneId, Name: &name, Type: &recordType, Value: &value, TTL: ttl}
+	api.records[id] = record
+	api.touch(zoneId)
+	return record
+}
+
+func (api *fakeAPI) touch(zoneId string) {
+	if zone, ok := api.zones[zoneId]; ok {
+		count := 0
+		for _, record := range api.records {
+			if *record.ZoneId == zoneId {
+				count++
+			}
+		}
+		zone.NumberOfRecords = &count
+		api.clock++
+		zone.Modified = ptr(time.Unix(int64(api.clock), 0).UTC().Format(time.RFC3339))
+	}
+}
+
+// zoneRecords returns the records of a zone sorted by name, type and value.
+func (api *fakeAPI) zoneRecords(zoneId string) []*Record {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	var records []*Record
+	for _, record := range api.records {
+		if *record.ZoneId == zoneId {
+			copy := *record
+			records = append(records, &copy)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return recordSortKey(records[i]) < recordSortKey(records[j])
+	})
+	return records
+}
+
+func (api *fakeAPI) callCount(prefix string) int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	count := 0
+	for _, call := range api.calls {
+		if strings.HasPrefix(call, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func recordSortKey(record *Record) string {
+	return strings.Join([]string{*record.Name, *record.Type, *record.Value}, "\x00")
+}
+
+func (api *fakeAPI) serve(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.Path, basePath)
+	api.calls = append(api.calls, r.Method+" "+path)
+	if api.failOn != nil && api.failOn(r.Method, path, body) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{}`)
+		return
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case parts[0] == "zones" && len(parts) == 1:
+		api.serveZones(w, r, body)
+	case parts[0] == "zones" && len(parts) == 2:
+		api.serveZone(w, r, parts[1], body)
+	case parts[0] == "records" && len(parts) == 1:
+		api.serveRecords(w, r, body)
+	case parts[0] == "records" && len(parts) == 2:
+		api.serveRecord(w, r, parts[1], body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (api *fakeAPI) writeJson(w http.ResponseWriter, status int, value interface{}) {
+	w.WriteHeader(status)
+	data, _ := json.Marshal(value)
+	w.Write(data)
+}
+
+func (api *fakeAPI) serveZones(w http.ResponseWriter, r *http.Request, body []byte) {
+	switch r.Method {
+	case "GET":
+		var zones []*Zone
+		name := r.URL.Query().Get("search_name")
+		for _, zone := range api.zones {
+			if strings.Contains(*zone.Name, name) {
+				copy := *zone
+				zones = append(zones, &copy)
+			}
+		}
+		sort.Slice(zones, func(i, j int) bool { return *zones[i].Name < *zones[j].Name })
+		lastPage := 1
+		api.writeJson(w, 200, &ZoneList{Zones: zones, Meta: &Meta{Pagination: &Pagination{LastPage: &lastPage}}})
+	case "POST":
+		request := new(ZoneRequest)
+		json.Unmarshal(body, request)
+		for _, zone := range api.zones {
+			if *zone.Name == *request.Name {
+				api.writeJson(w, 422, &ZoneResponse{})
+				return
+			}
+		}
+		id := api.id()
+		ttl := 86400
+		if request.TTL != nil {
+			ttl = *request.TTL
+		}
+		zone := &Zone{Id: &id, Name: request.Name, TTL: &ttl, NumberOfRecords: new(int)}
+		api.zones[id] = zone
+		api.touch(id)
+		api.writeJson(w, 200, &ZoneResponse{Zone: zone})
+	}
+}
+
+func (api *fakeAPI) serveZone(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	zone, ok := api.zones[id]
+	if !ok {
+		api.writeJson(w, 404, &ZoneResponse{})
+		return
+	}
+	switch r.Method {
+	case "GET":
+		api.writeJson(w, 200, &ZoneResponse{Zone: zone})
+	case "PUT":
+		request := new(ZoneRequest)
+		json.Unmarshal(body, request)
+		if request.Name != nil {
+			zone.Name = request.Name
+		}
+		if request.TTL != nil {
+			zone.TTL = request.TTL
+		}
+		api.touch(id)
+		api.writeJson(w, 200, &ZoneResponse{Zone: zone})
+	case "DELETE":
+		delete(api.zones, id)
+		for recordId, record := range api.records {
+			if *record.ZoneId == id {
+				delete(api.records, recordId)
+			}
+		}
+		w.WriteHeader(200)
+	}
+}
+
+func (api *fakeAPI) serveRecords(w http.ResponseWriter, r *http.Request, body []byte) {
+	switch r.Method {
+	case "GET":
+		zoneId := r.URL.Query().Get("zone_id")
+		records := []*Record{}
+		for _, record := range api.records {
+			if *record.ZoneId == zoneId {
+				copy := *record
+				records = append(records, &copy)
+			}
+		}
+		sort.Slice(records, func(i, j int) bool { return *records[i].Id < *records[j].Id })
+		api.writeJson(w, 200, &Records{Records: records})
+	case "POST":
+		record := new(Record)
+		json.Unmarshal(body, record)
+		if record.ZoneId == nil || api.zones[*record.ZoneId] == nil {
+			api.writeJson(w, 422, &RecordResponse{})
+			return
+		}
+		id := api.id()
+		record.Id = &id
+		api.records[id] = record
+		api.touch(*record.ZoneId)
+		api.writeJson(w, 200, &RecordResponse{Record: record})
+	}
+}
+
+func (api *fakeAPI) serveRecord(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	record, ok := api.records[id]
+	if !ok {
+		api.writeJson(w, 404, &RecordResponse{})
+		return
+	}
+	switch r.Method {
+	case "GET":
+		api.writeJson(w, 200, &RecordResponse{Record: record})
+	case "PUT":
+		update := new(Record)
+		json.Unmarshal(body, update)
+		update.Id = &id
+		api.records[id] = update
+		api.touch(*update.ZoneId)
+		api.writeJson(w, 200, &RecordResponse{Record: update})
+	case "DELETE":
+		delete(api.records, id)
+		api.touch(*record.ZoneId)
+		w.WriteHeader(200)
+	}
+}
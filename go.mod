@@ -4,11 +4,13 @@ go 1.21.0
 
 require (
 	github.com/go-resty/resty/v2 v2.11.0
+	golang.org/x/net v0.17.0
+	golang.org/x/time v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
 	gotest.tools v2.2.0+incompatible
 )
 
 require (
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	golang.org/x/net v0.17.0 // indirect
 )
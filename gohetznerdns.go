@@ -1,5 +1,7 @@
 package gohetznerdns
 
+import "golang.org/x/time/rate"
+
 // Hetzner DNS Public API interface entry interface
 // Exposes DNS and Record service to manage DNS Zone and records.
 // See api documentation for more information [https://dns.hetzner.com/api-docs]
@@ -16,6 +18,35 @@ type HetznerDNS interface {
 
 	// Returns Record Service
 	GetRecordService() RecordService
+
+	// Configures the journal receiving an entry for every mutating zone and record call.
+	// Actor labels the entries, a nil sink disables journaling.
+	SetJournal(sink JournalSink, actor string)
+
+	// Applies the inverse of the journal entry with the given id. Requires a sink implementing [JournalReader].
+	Revert(entryId string) error
+
+	// Applies the inverse of the journal entry. Deleted records and zones are recreated with new ids.
+	RevertEntry(entry *JournalEntry) error
+
+	// Configures the read-through cache of zone and record reads, a nil options disables it.
+	// Writes through this client invalidate the affected entries.
+	SetCache(options *CacheOptions)
+
+	// Returns a view of this client whose reads skip the cache. It shares the configuration,
+	// journal and cache of this client, and its responses still refresh the cache.
+	WithoutCache() HetznerDNS
+
+	// Enables or disables sharing one API call between identical reads in flight at the same
+	// time. Coalescing is enabled by default.
+	SetCoalescing(enabled bool)
+
+	// Returns the counts of coalesced reads.
+	CoalescingStats() CoalescingStats
+
+	// Limits the API calls of this client to requestsPerSecond with bursts of up to burst
+	// calls. Calls wait for their turn. A zero requestsPerSecond removes the limit.
+	SetRateLimit(requestsPerSecond float64, burst int)
 }
 
 type hetznerDNS struct {
@@ -50,6 +81,14 @@ func (dns *hetznerDNS) SetToken(token string) error {
 	return nil
 }
 
+func (dns *hetznerDNS) SetRateLimit(requestsPerSecond float64, burst int) {
+	if requestsPerSecond <= 0 {
+		dns.client.limiter = nil
+		return
+	}
+	dns.client.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), max(burst, 1))
+}
+
 func (dns *hetznerDNS) GetZoneService() ZoneService {
 	return dns.ZoneService
 }
@@ -0,0 +1,367 @@
+package gohetznerdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Record types that can be imported into Hetzner DNS.
+var importableRecordTypes = []string{"A", "AAAA", "NS", "MX", "CNAME", "TXT", "SRV", "CAA", "TLSA", "DS"}
+
+// Record or feature of a source definition that cannot be represented in Hetzner DNS. The
+// record is skipped unless the message says otherwise.
+type ImportIssue struct {
+	Zone    string
+	Name    string
+	Type    string
+	Message string
+}
+
+func (issue *ImportIssue) String() string {
+	return fmt.Sprintf("%s %s %s: %s", issue.Zone, issue.Name, issue.Type, issue.Message)
+}
+
+// Options of [ImportRecords].
+type ImportOptions struct {
+	// Deletes the records of an existing zone that are absent from the source. Without it
+	// they are kept and reported in [ImportResult.Extra].
+	Prune bool
+}
+
+// Outcome of importing one zone.
+type ImportResult struct {
+	Zone        *Zone
+	ZoneCreated bool
+	Summary     *ChangeSummary
+	Unsupported []*ImportIssue
+	// Records absent from the source that were kept because pruning is off.
+	Extra []*Record
+}
+
+// Parses an OctoDNS YAML zone file. Records without ttl get the OctoDNS default of 3600.
+func ParseOctoDNS(zoneName string, data []byte) ([]*Record, []*ImportIssue, error) {
+	var document map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("octodns: %w", err)
+	}
+	names := make([]string, 0, len(document))
+	for name := range document {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var records []*Record
+	var issues []*ImportIssue
+	for _, name := range names {
+		node := document[name]
+		var entries []*octoDNSRecord
+		if node.Kind == yaml.SequenceNode {
+			if err := node.Decode(&entries); err != nil {
+				return nil, nil, fmt.Errorf("octodns: %s: %w", name, err)
+			}
+		} else {
+			entry := new(octoDNSRecord)
+			if err := node.Decode(entry); err != nil {
+				return nil, nil, fmt.Errorf("octodns: %s: %w", name, err)
+			}
+			entries = append(entries, entry)
+		}
+		for _, entry := range entries {
+			converted, entryIssues := entry.records(iif(name == "", "@", name))
+			records = append(records, converted...)
+			for _, issue := range entryIssues {
+				issue.Zone = zoneName
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return records, issues, nil
+}
+
+type octoDNSRecord struct {
+	Type    string                 `yaml:"type"`
+	TTL     *int                   `yaml:"ttl"`
+	Value   interface{}            `yaml:"value"`
+	Values  []interface{}          `yaml:"values"`
+	Octodns map[string]interface{} `yaml:"octodns"`
+	Dynamic interface{}            `yaml:"dynamic"`
+	Geo     interface{}            `yaml:"geo"`
+}
+
+func (entry *octoDNSRecord) records(name string) ([]*Record, []*ImportIssue) {
+	recordType := strings.ToUpper(entry.Type)
+	issue := func(format string, args ...interface{}) *ImportIssue {
+		return &ImportIssue{Name: name, Type: recordType, Message: fmt.Sprintf(format, args...)}
+	}
+	var issues []*ImportIssue
+	if recordType == "SPF" {
+		recordType = "TXT"
+	}
+	if !containsFold(importableRecordTypes, recordType) {
+		return nil, []*ImportIssue{issue("record type is not supported")}
+	}
+	if len(entry.Octodns) > 0 {
+		issues = append(issues, issue("provider-specific octodns settings are ignored"))
+	}
+	if entry.Dynamic != nil || entry.Geo != nil {
+		issues = append(issues, issue("dynamic and geo rules are ignored, only the default values are imported"))
+	}
+	values := entry.Values
+	if entry.Value != nil {
+		values = append([]interface{}{entry.Value}, values...)
+	}
+	ttl := iif(entry.TTL != nil, entry.TTL, ptr(3600))
+	var records []*Record
+	for _, value := range values {
+		converted, err := octoDNSValue(recordType, value)
+		if err != nil {
+			issues = append(issues, issue("%s", err))
+			continue
+		}
+		records = append(records, &Record{Name: ptr(name), Type: ptr(recordType), Value: ptr(converted), TTL: ttl})
+	}
+	return records, issues
+}
+
+// Converts a single OctoDNS value into the value format of the Hetzner API.
+func octoDNSValue(recordType string, value interface{}) (string, error) {
+	if fields, ok := value.(map[string]interface{}); ok {
+		field := func(keys ...string) string {
+			for _, key := range keys {
+				if value, ok := fields[key]; ok {
+					return fmt.Sprint(value)
+				}
+			}
+			return ""
+		}
+		var parts []string
+		switch recordType {
+		case "MX":
+			parts = []string{field("preference", "priority"), field("exchange", "value")}
+		case "SRV":
+			parts = []string{field("priority"), field("weight"), field("port"), field("target")}
+		case "CAA":
+			parts = []string{field("flags"), field("tag"), quoteTXT(field("value"))}
+			parts[0] = iif(parts[0] == "", "0", parts[0])
+		case "TLSA":
+			parts = []string{field("certificate_usage"), field("selector"), field("matching_type"), field("certificate_association_data")}
+		case "DS":
+			parts = []string{field("key_tag"), field("algorithm"), field("digest_type"), field("digest")}
+		default:
+			return "", fmt.Errorf("unexpected structured value")
+		}
+		for _, part := range parts {
+			if part == "" || part == `""` {
+				return "", fmt.Errorf("incomplete value %v", value)
+			}
+		}
+		return strings.Join(parts, " "), nil
+	}
+	text := fmt.Sprint(value)
+	switch recordType {
+	case "MX", "SRV", "CAA", "TLSA", "DS":
+		return "", fmt.Errorf("expected structured value, got %q", text)
+	case "TXT":
+		return strings.ReplaceAll(text, `\;`, ";"), nil
+	}
+	return text, nil
+}
+
+// Parses the JSON written by dnscontrol print-ir.
+func ParseDNSControl(data []byte) (map[string][]*Record, []*ImportIssue, error) {
+	document := new(dnsControlIR)
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, nil, fmt.Errorf("dnscontrol: %w", err)
+	}
+	zones := map[string][]*Record{}
+	var issues []*ImportIssue
+	for _, domain := range document.Domains {
+		zones[domain.Name] = []*Record{}
+		for _, record := range domain.Records {
+			converted, issue := record.record()
+			if issue != nil {
+				issue.Zone = domain.Name
+				issues = append(issues, issue)
+			}
+			if converted != nil {
+				zones[domain.Name] = append(zones[domain.Name], converted)
+			}
+		}
+	}
+	return zones, issues, nil
+}
+
+type dnsControlIR struct {
+	Domains []*struct {
+		Name    string              `json:"name"`
+		Records []*dnsControlRecord `json:"records"`
+	} `json:"domains"`
+}
+
+type dnsControlRecord struct {
+	Type             string            `json:"type"`
+	Name             string            `json:"name"`
+	Target           string            `json:"target"`
+	TTL              *int              `json:"ttl"`
+	Meta             map[string]string `json:"meta"`
+	MxPreference     int               `json:"mxpreference"`
+	SrvPriority      int               `json:"srvpriority"`
+	SrvWeight        int               `json:"srvweight"`
+	SrvPort          int               `json:"srvport"`
+	CaaTag           string            `json:"caatag"`
+	CaaFlag          int               `json:"caaflag"`
+	TlsaUsage        int               `json:"tlsausage"`
+	TlsaSelector     int               `json:"tlsaselector"`
+	TlsaMatchingType int               `json:"tlsamatchingtype"`
+	DsKeyTag         int               `json:"dskeytag"`
+	DsAlgorithm      int               `json:"dsalgorithm"`
+	DsDigestType     int               `json:"dsdigesttype"`
+	DsDigest         string            `json:"dsdigest"`
+	Txts             []string          `json:"txts"`
+}
+
+func (record *dnsControlRecord) record() (*Record, *ImportIssue) {
+	name := iif(record.Name == "", "@", record.Name)
+	if !containsFold(importableRecordTypes, record.Type) {
+		return nil, &ImportIssue{Name: name, Type: record.Type, Message: "record type is not supported"}
+	}
+	var value string
+	switch record.Type {
+	case "MX":
+		value = fmt.Sprintf("%d %s", record.MxPreference, record.Target)
+	case "SRV":
+		value = fmt.Sprintf("%d %d %d %s", record.SrvPriority, record.SrvWeight, record.SrvPort, record.Target)
+	case "CAA":
+		value = fmt.Sprintf("%d %s %s", record.CaaFlag, record.CaaTag, quoteTXT(record.Target))
+	case "TLSA":
+		value = fmt.Sprintf("%d %d %d %s", record.TlsaUsage, record.TlsaSelector, record.TlsaMatchingType, record.Target)
+	case "DS":
+		value = fmt.Sprintf("%d %d %d %s", record.DsKeyTag, record.DsAlgorithm, record.DsDigestType, record.DsDigest)
+	case "TXT":
+		value = iif(len(record.Txts) > 0, strings.Join(record.Txts, ""), record.Target)
+	default:
+		value = record.Target
+	}
+	converted := &Record{Name: ptr(name), Type: ptr(record.Type), Value: ptr(value), TTL: record.TTL}
+	if len(record.Meta) == 0 {
+		return converted, nil
+	}
+	keys := make([]string, 0, len(record.Meta))
+	for key := range record.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return converted, &ImportIssue{Name: name, Type: record.Type, Message: fmt.Sprintf("provider-specific metadata %s is ignored, the record is imported", strings.Join(keys, ", "))}
+}
+
+// Reads an OctoDNS YAML zone file and imports it into the zone with the given name.
+// See [ImportRecords].
+func ImportOctoDNS(dns HetznerDNS, zoneName string, data []byte, options *ImportOptions) (*ImportResult, error) {
+	records, issues, err := ParseOctoDNS(zoneName, data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ImportRecords(dns, zoneName, records, options)
+	if result != nil {
+		result.Unsupported = issues
+	}
+	return result, err
+}
+
+// Reads dnscontrol print-ir output and imports every domain in it. See [ImportRecords].
+func ImportDNSControl(dns HetznerDNS, data []byte, options *ImportOptions) ([]*ImportResult, error) {
+	zones, issues, err := ParseDNSControl(data)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var results []*ImportResult
+	for _, name := range names {
+		result, err := ImportRecords(dns, name, zones[name], options)
+		if result != nil {
+			for _, issue := range issues {
+				if issue.Zone == name {
+					result.Unsupported = append(result.Unsupported, issue)
+				}
+			}
+			results = append(results, result)
+		}
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return results, nil
+}
+
+// Creates the zone when missing and creates or updates its records to match the given ones,
+// leaving the SOA and apex NS records to Hetzner. Records absent from the list are only deleted
+// with [ImportOptions.Prune], a nil options uses the defaults. TTLs equal to the zone TTL are
+// dropped so the records inherit it.
+func ImportRecords(dns HetznerDNS, zoneName string, records []*Record, options *ImportOptions) (*ImportResult, error) {
+	if err := validateNotEmpty("zoneName", &zoneName); err != nil {
+		return nil, err
+	}
+	dns = dns.WithoutCache()
+	result := &ImportResult{}
+	zones, err := dns.GetZoneService().GetAllZonesByName(&zoneName)
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range zones {
+		if strings.EqualFold(deref(zone.Name), zoneName) {
+			result.Zone = zone
+		}
+	}
+	if result.Zone == nil {
+		if result.Zone, err = dns.GetZoneService().CreateZone(&ZoneRequest{Name: &zoneName}); err != nil {
+			return nil, err
+		}
+		result.ZoneCreated = true
+	}
+	desired := make([]*Record, 0, len(records))
+	for _, record := range records {
+		record = copyRecord(record, false)
+		record.ZoneId = result.Zone.Id
+		if sameTTL(record.TTL, result.Zone.TTL) {
+			record.TTL = nil
+		}
+		desired = append(desired, record)
+	}
+	current, err := dns.GetRecordService().GetAllRecords(result.Zone.Id)
+	if err != nil {
+		return result, err
+	}
+	prune := options != nil && options.Prune
+	var creates, changes []*RecordChange
+	for _, change := range planZoneChanges(result.Zone.Id, current, desired) {
+		switch {
+		case prune:
+		case change.Action == ChangeDelete:
+			result.Extra = append(result.Extra, change.Before)
+			continue
+		case change.Action == ChangeUpdate && !sameRecordValue(change.Before.Type, change.Before.Value, change.After.Value):
+			// The surplus record would be reused for a new value, keep it and create the value.
+			result.Extra = append(result.Extra, change.Before)
+			create := copyRecord(change.After, false)
+			create.TTL = nil
+			for _, record := range filterRecords(desired, create.Name, create.Type) {
+				if sameRecordValue(record.Type, record.Value, create.Value) {
+					create.TTL = record.TTL
+				}
+			}
+			creates = append(creates, &RecordChange{Action: ChangeCreate, After: create})
+			continue
+		}
+		changes = append(changes, change)
+	}
+	applied, err := applyRecordChanges(dns.GetRecordService(), append(creates, changes...))
+	result.Summary = newSyncSummary(applied, desired)
+	return result, err
+}
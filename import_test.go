@@ -0,0 +1,167 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const octoDNSZone = `---
+'':
+  - type: A
+    values:
+      - 192.0.2.1
+      - 192.0.2.2
+  - type: MX
+    ttl: 300
+    values:
+      - exchange: mail.example.com.
+        preference: 10
+  - type: TXT
+    value: v=spf1 mx\; -all
+  - type: NS
+    values:
+      - ns1.example.net.
+  - type: ALIAS
+    value: lb.example.net.
+_sip._tcp:
+  type: SRV
+  values:
+    - priority: 10
+      weight: 5
+      port: 5060
+      target: sip.example.com.
+www:
+  type: CNAME
+  value: example.com.
+  octodns:
+    cloudflare:
+      proxied: true
+`
+
+func TestParseOctoDNS(t *testing.T) {
+	records, issues, err := ParseOctoDNS("example.com", []byte(octoDNSZone))
+	assert.NilError(t, err)
+	var got []string
+	for _, record := range records {
+		got = append(got, (&RecordChange{Action: ChangeCreate, After: record}).String())
+	}
+	assert.DeepEqual(t, got, []string{
+		"create @ A 192.0.2.1",
+		"create @ A 192.0.2.2",
+		"create @ MX 10 mail.example.com.",
+		"create @ TXT v=spf1 mx; -all",
+		"create @ NS ns1.example.net.",
+		"create _sip._tcp SRV 10 5 5060 sip.example.com.",
+		"create www CNAME example.com.",
+	})
+	assert.Equal(t, *records[2].TTL, 300)
+	assert.Equal(t, *records[0].TTL, 3600)
+	assert.Equal(t, len(issues), 2)
+	assert.Equal(t, issues[0].String(), "example.com @ ALIAS: record type is not supported")
+	assert.Equal(t, issues[1].String(), "example.com www CNAME: provider-specific octodns settings are ignored")
+}
+
+func TestImportOctoDNS(t *testing.T) {
+	api := newFakeAPI(t)
+	dns := api.dns(t)
+
+	result, err := ImportOctoDNS(dns, "example.com", []byte(octoDNSZone), nil)
+	assert.NilError(t, err)
+	assert.Assert(t, result.ZoneCreated)
+	assert.Equal(t, result.Summary.Created, 6)
+	assert.Equal(t, len(result.Unsupported), 2)
+	records := recordValues(api.zoneRecords(*result.Zone.Id), "@", "A")
+	assert.DeepEqual(t, records, []string{"192.0.2.1", "192.0.2.2"})
+
+	result, err = ImportOctoDNS(dns, "example.com", []byte(octoDNSZone), nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !result.ZoneCreated)
+	assert.Equal(t, result.Summary.Created+result.Summary.Updated+result.Summary.Deleted, 0)
+	assert.Equal(t, result.Summary.Unchanged, 6)
+}
+
+const dnsControlPrintIR = `{
+  "registrars": [{"name": "none", "type": "NONE"}],
+  "dns_providers": [{"name": "hetzner", "type": "HETZNER"}],
+  "domains": [
+    {
+      "name": "example.org",
+      "records": [
+        {"type": "A", "name": "@", "target": "192.0.2.1", "ttl": 86400},
+        {"type": "A", "name": "www", "target": "192.0.2.1", "ttl": 300, "meta": {"cloudflare_proxy": "on"}},
+        {"type": "MX", "name": "@", "target": "mail.example.org.", "mxpreference": 10, "ttl": 86400},
+        {"type": "CAA", "name": "@", "target": "letsencrypt.org", "caatag": "issue", "caaflag": 0, "ttl": 86400},
+        {"type": "TXT", "name": "@", "target": "v=spf1 -all", "txts": ["v=spf1 ", "-all"], "ttl": 86400},
+        {"type": "ALIAS", "name": "@", "target": "lb.example.net.", "ttl": 86400}
+      ]
+    }
+  ]
+}`
+
+func TestImportDNSControl(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.org", 86400)
+	api.addRecord(*zone.Id, "old", "A", "192.0.2.9", nil)
+
+	results, err := ImportDNSControl(api.dns(t), []byte(dnsControlPrintIR), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(results), 1)
+	assert.Assert(t, !results[0].ZoneCreated)
+	assert.Equal(t, results[0].Summary.Created, 5)
+	assert.Equal(t, results[0].Summary.Deleted, 0)
+	assert.Equal(t, len(results[0].Extra), 1)
+	assert.Equal(t, *results[0].Extra[0].Name, "old")
+	assert.Equal(t, len(results[0].Unsupported), 2)
+	assert.Equal(t, results[0].Unsupported[0].Message, "provider-specific metadata cloudflare_proxy is ignored, the record is imported")
+	assert.Equal(t, results[0].Unsupported[1].Message, "record type is not supported")
+
+	records := api.zoneRecords(*zone.Id)
+	assert.DeepEqual(t, recordValues(records, "old", "A"), []string{"192.0.2.9"})
+	assert.DeepEqual(t, recordValues(records, "@", "CAA"), []string{`0 issue "letsencrypt.org"`})
+	assert.DeepEqual(t, recordValues(records, "@", "TXT"), []string{"v=spf1 -all"})
+	for _, record := range records {
+		if *record.Name == "www" {
+			assert.Equal(t, *record.TTL, 300)
+		} else {
+			assert.Assert(t, record.TTL == nil)
+		}
+	}
+}
+
+func TestImportRecordsPrune(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.org", 86400)
+	api.addRecord(*zone.Id, "old", "A", "192.0.2.9", nil)
+
+	result, err := ImportRecords(api.dns(t), "example.org", []*Record{
+		{Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")},
+	}, &ImportOptions{Prune: true})
+	assert.NilError(t, err)
+	assert.Equal(t, result.Summary.Created, 1)
+	assert.Equal(t, result.Summary.Deleted, 1)
+	assert.Equal(t, len(result.Extra), 0)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "old", "A"), []string(nil))
+}
+
+func TestImportRecordsKeepsSharedRRSet(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.org", 86400)
+	old := api.addRecord(*zone.Id, "@", "A", "192.0.2.9", ptr(300))
+
+	result, err := ImportRecords(api.dns(t), "example.org", []*Record{
+		{Name: ptr("@"), Type: ptr("A"), Value: ptr("192.0.2.1")},
+	}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result.Summary.Created, 1)
+	assert.Equal(t, result.Summary.Updated, 0)
+	assert.Equal(t, len(result.Extra), 1)
+	assert.Equal(t, *result.Extra[0].Id, *old.Id)
+	records := api.zoneRecords(*zone.Id)
+	assert.DeepEqual(t, recordValues(records, "@", "A"), []string{"192.0.2.1", "192.0.2.9"})
+	for _, record := range records {
+		if *record.Value == "192.0.2.1" {
+			assert.Assert(t, record.TTL == nil)
+		}
+	}
+}
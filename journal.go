@@ -0,0 +1,227 @@
+package gohetznerdns
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Mutating operations recorded in the journal.
+const (
+	OperationCreateRecord   = "CreateRecord"
+	OperationUpdateRecord   = "UpdateRecord"
+	OperationDeleteRecord   = "DeleteRecord"
+	OperationCreateZone     = "CreateZone"
+	OperationUpdateZone     = "UpdateZone"
+	OperationDeleteZone     = "DeleteZone"
+	OperationImportZoneFile = "ImportZoneFile"
+)
+
+// Journal entry describing a single mutating call and the state before and after it.
+// RecordsBefore holds the records of a zone replaced by a zone import or removed with it.
+type JournalEntry struct {
+	Id            string    `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Actor         string    `json:"actor,omitempty"`
+	Operation     string    `json:"operation"`
+	RecordBefore  *Record   `json:"record_before,omitempty"`
+	RecordAfter   *Record   `json:"record_after,omitempty"`
+	ZoneBefore    *Zone     `json:"zone_before,omitempty"`
+	ZoneAfter     *Zone     `json:"zone_after,omitempty"`
+	RecordsBefore []*Record `json:"records_before,omitempty"`
+}
+
+// Receives journal entries.
+type JournalSink interface {
+	WriteEntry(entry *JournalEntry) error
+}
+
+// Journal sink that can look entries up, required by [HetznerDNS.Revert].
+type JournalReader interface {
+	ReadEntry(id string) (*JournalEntry, error)
+}
+
+// Adapts a function to a [JournalSink].
+type JournalSinkFunc func(entry *JournalEntry) error
+
+func (sink JournalSinkFunc) WriteEntry(entry *JournalEntry) error {
+	return sink(entry)
+}
+
+// Appends entries as JSON lines to a file.
+type FileJournal struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (journal *FileJournal) WriteEntry(entry *JournalEntry) error {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(journal.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Returns all entries in the order they were written.
+func (journal *FileJournal) Entries() ([]*JournalEntry, error) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	file, err := os.Open(journal.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []*JournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		entry := new(JournalEntry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func (journal *FileJournal) ReadEntry(id string) (*JournalEntry, error) {
+	entries, err := journal.Entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Id == id {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("journal: entry %s not found", id)
+}
+
+type journal struct {
+	sink  JournalSink
+	actor string
+}
+
+func newRandomId() string {
+	data := make([]byte, 8)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// Writes an entry when a journal is configured. Journal failures are returned to the caller
+// of the mutating call even though the change itself was applied.
+func (client *client) writeJournal(entry *JournalEntry) error {
+	if client.journal == nil {
+		return nil
+	}
+	entry.Id = newRandomId()
+	entry.Timestamp = time.Now().UTC()
+	entry.Actor = client.journal.actor
+	if err := client.journal.sink.WriteEntry(entry); err != nil {
+		return &journalError{err: err}
+	}
+	return nil
+}
+
+// Error of a journal sink, returned after the journaled call itself succeeded.
+type journalError struct {
+	err error
+}
+
+func (err *journalError) Error() string {
+	return "journal: " + err.err.Error()
+}
+
+func (err *journalError) Unwrap() error {
+	return err.err
+}
+
+func isJournalError(err error) bool {
+	var journal *journalError
+	return errors.As(err, &journal)
+}
+
+func (client *client) journaling() bool {
+	return client.journal != nil
+}
+
+func (dns *hetznerDNS) SetJournal(sink JournalSink, actor string) {
+	if sink == nil {
+		dns.client.journal = nil
+		return
+	}
+	dns.client.journal = &journal{sink: sink, actor: actor}
+}
+
+func (dns *hetznerDNS) Revert(entryId string) error {
+	if dns.client.journal == nil {
+		return fmt.Errorf("journal: not configured")
+	}
+	reader, ok := dns.client.journal.sink.(JournalReader)
+	if !ok {
+		return fmt.Errorf("journal: sink cannot read entries")
+	}
+	entry, err := reader.ReadEntry(entryId)
+	if err != nil {
+		return err
+	}
+	return dns.RevertEntry(entry)
+}
+
+func (dns *hetznerDNS) RevertEntry(entry *JournalEntry) error {
+	records := dns.GetRecordService()
+	zones := dns.GetZoneService()
+	switch entry.Operation {
+	case OperationCreateRecord:
+		return records.DeleteRecord(entry.RecordAfter.Id)
+	case OperationUpdateRecord:
+		_, err := records.UpdateRecord(copyRecord(entry.RecordBefore, true))
+		return err
+	case OperationDeleteRecord:
+		_, err := records.CreateRecord(copyRecord(entry.RecordBefore, false))
+		return err
+	case OperationCreateZone:
+		return zones.DeleteZone(entry.ZoneAfter.Id)
+	case OperationUpdateZone:
+		_, err := zones.UpdateZone(entry.ZoneBefore.Id, &ZoneRequest{Name: entry.ZoneBefore.Name, TTL: entry.ZoneBefore.TTL})
+		return err
+	case OperationDeleteZone:
+		zone, err := zones.CreateZone(&ZoneRequest{Name: entry.ZoneBefore.Name, TTL: entry.ZoneBefore.TTL})
+		if err != nil {
+			return err
+		}
+		return dns.restoreRecords(zone.Id, entry.RecordsBefore)
+	case OperationImportZoneFile:
+		return dns.restoreRecords(entry.ZoneBefore.Id, entry.RecordsBefore)
+	}
+	return fmt.Errorf("journal: cannot revert operation %q", entry.Operation)
+}
+
+// Brings the records of the zone back to the given state, leaving managed records alone.
+func (dns *hetznerDNS) restoreRecords(zoneId *string, records []*Record) error {
+	current, err := dns.WithoutCache().GetRecordService().GetAllRecords(zoneId)
+	if err != nil {
+		return err
+	}
+	_, err = applyRecordChanges(dns.GetRecordService(), planZoneChanges(zoneId, current, records))
+	return err
+}
@@ -0,0 +1,146 @@
+package gohetznerdns
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestJournalRecordsMutations(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	journal := &FileJournal{Path: filepath.Join(t.TempDir(), "journal.jsonl")}
+	dns.SetJournal(journal, "deploy-bot")
+	records := dns.GetRecordService()
+
+	created, err := records.CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")})
+	assert.NilError(t, err)
+	_, err = records.UpdateRecord(&Record{Id: created.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.2")})
+	assert.NilError(t, err)
+	assert.NilError(t, records.DeleteRecord(created.Id))
+	assert.NilError(t, records.DeleteRecord(ptr("missing")))
+
+	entries, err := journal.Entries()
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0].Operation, OperationCreateRecord)
+	assert.Equal(t, entries[0].Actor, "deploy-bot")
+	assert.Equal(t, *entries[1].RecordBefore.Value, "192.0.2.1")
+	assert.Equal(t, *entries[1].RecordAfter.Value, "192.0.2.2")
+	assert.Equal(t, entries[2].Operation, OperationDeleteRecord)
+	assert.Equal(t, *entries[2].RecordBefore.Value, "192.0.2.2")
+
+	assert.NilError(t, dns.Revert(entries[2].Id))
+	restored := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(restored), 1)
+	assert.Equal(t, *restored[0].Value, "192.0.2.2")
+
+	entries, _ = journal.Entries()
+	assert.Equal(t, len(entries), 4)
+	assert.NilError(t, dns.Revert(entries[3].Id))
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 0)
+}
+
+func TestJournalRevertUpdate(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	record := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", ptr(300))
+	dns := api.dns(t)
+	var entries []*JournalEntry
+	dns.SetJournal(JournalSinkFunc(func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}), "")
+
+	_, err := dns.GetRecordService().UpdateRecord(&Record{Id: record.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.9")})
+	assert.NilError(t, err)
+	assert.NilError(t, dns.RevertEntry(entries[0]))
+	restored := api.zoneRecords(*zone.Id)[0]
+	assert.Equal(t, *restored.Id, *record.Id)
+	assert.Equal(t, *restored.Value, "192.0.2.1")
+	assert.Equal(t, *restored.TTL, 300)
+
+	assert.Error(t, dns.Revert(entries[0].Id), "journal: sink cannot read entries")
+}
+
+func TestJournalRevertZoneOperations(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "@", "MX", "10 mail.example.com.", nil)
+	dns := api.dns(t)
+	var entries []*JournalEntry
+	dns.SetJournal(JournalSinkFunc(func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}), "")
+	zones := dns.GetZoneService()
+
+	_, err := zones.UpdateZone(zone.Id, &ZoneRequest{Name: zone.Name, TTL: ptr(60)})
+	assert.NilError(t, err)
+	assert.NilError(t, dns.RevertEntry(entries[0]))
+	assert.Equal(t, *api.zones[*zone.Id].TTL, 3600)
+
+	assert.NilError(t, zones.DeleteZone(zone.Id))
+	deleted := entries[len(entries)-1]
+	assert.Equal(t, deleted.Operation, OperationDeleteZone)
+	assert.Equal(t, len(deleted.RecordsBefore), 2)
+	assert.NilError(t, dns.RevertEntry(deleted))
+	recreated, err := zones.GetAllZonesByName(ptr("example.com"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(recreated), 1)
+	assert.Equal(t, len(api.zoneRecords(*recreated[0].Id)), 2)
+
+	created := entries[len(entries)-3]
+	assert.Equal(t, created.Operation, OperationCreateZone)
+	assert.NilError(t, dns.RevertEntry(created))
+	assert.Equal(t, len(api.zones), 0)
+}
+
+func TestJournalSinkFailure(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	dns.SetJournal(JournalSinkFunc(func(entry *JournalEntry) error { return errors.New("disk full") }), "")
+
+	created, err := dns.GetRecordService().CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")})
+	assert.Error(t, err, "journal: disk full")
+	assert.Assert(t, created != nil)
+
+	dns.SetJournal(nil, "")
+	assert.Error(t, dns.Revert("x"), "journal: not configured")
+}
+
+func TestJournalDeleteFailsWithoutSnapshot(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	record := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	dns := api.dns(t)
+	var entries []*JournalEntry
+	dns.SetJournal(JournalSinkFunc(func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}), "")
+	api.failOn = func(method, path string, body []byte) bool { return method == "GET" }
+
+	assert.Error(t, dns.GetRecordService().DeleteRecord(record.Id), "500 Internal Server Error")
+	assert.Error(t, dns.GetZoneService().DeleteZone(zone.Id), "500 Internal Server Error")
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+	assert.Equal(t, len(api.zones), 1)
+	assert.Equal(t, len(entries), 0)
+	assert.Equal(t, api.callCount("DELETE"), 0)
+
+	api.failOn = nil
+	assert.NilError(t, dns.GetRecordService().DeleteRecord(ptr("missing")))
+	assert.NilError(t, dns.GetZoneService().DeleteZone(ptr("missing")))
+	assert.Equal(t, len(entries), 0)
+}
+
+func TestFileJournalReadEntryMissing(t *testing.T) {
+	journal := &FileJournal{Path: filepath.Join(t.TempDir(), "journal.jsonl")}
+	_, err := journal.ReadEntry("x")
+	assert.Error(t, err, "journal: entry x not found")
+}
@@ -0,0 +1,300 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity of a lint issue.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Problem reported by a lint rule. Record is nil for zone wide issues.
+type LintIssue struct {
+	Rule     string
+	Severity Severity
+	Record   *Record
+	Message  string
+}
+
+func (issue *LintIssue) String() string {
+	if issue.Record == nil {
+		return fmt.Sprintf("%s [%s] %s", issue.Severity, issue.Rule, issue.Message)
+	}
+	return fmt.Sprintf("%s [%s] %s %s: %s", issue.Severity, issue.Rule, deref(issue.Record.Name), deref(issue.Record.Type), issue.Message)
+}
+
+// Zone content handed to lint rules.
+type LintZone struct {
+	// Zone name without trailing dot.
+	Name    string
+	Records []*Record
+}
+
+// Returns the records of the given owner name, optionally restricted to a type.
+func (zone *LintZone) RecordsAt(name string, recordType string) []*Record {
+	var records []*Record
+	for _, record := range zone.Records {
+		if sameRecordName(record.Name, &name) && (recordType == "" || sameRecordType(record.Type, &recordType)) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Returns the owner name relative to the zone for a target host name, or false when the target
+// is outside the zone. Targets without trailing dot are relative to the zone.
+func (zone *LintZone) RelativeName(target string) (string, bool) {
+	if !strings.HasSuffix(target, ".") {
+		return normalizeRecordName(target), true
+	}
+	origin := normalizeOrigin(zone.Name)
+	lower := strings.ToLower(target)
+	if lower == origin {
+		return "@", true
+	}
+	if strings.HasSuffix(lower, "."+origin) {
+		return lower[:len(lower)-len(origin)-1], true
+	}
+	return "", false
+}
+
+// Check run by the [Linter]. Implement it to add custom policies.
+type LintRule interface {
+	Name() string
+	Check(zone *LintZone) []*LintIssue
+}
+
+type lintRuleFunc struct {
+	name  string
+	check func(zone *LintZone) []*LintIssue
+}
+
+func (rule *lintRuleFunc) Name() string {
+	return rule.name
+}
+
+func (rule *lintRuleFunc) Check(zone *LintZone) []*LintIssue {
+	return rule.check(zone)
+}
+
+// Creates a lint rule from a function.
+func NewLintRule(name string, check func(zone *LintZone) []*LintIssue) LintRule {
+	return &lintRuleFunc{name: name, check: check}
+}
+
+// Runs lint rules over the records of a zone.
+type Linter struct {
+	rules []LintRule
+}
+
+// Creates a linter with the given rules, or with [DefaultLintRules] when none are given.
+func NewLinter(rules ...LintRule) *Linter {
+	if len(rules) == 0 {
+		rules = DefaultLintRules()
+	}
+	return &Linter{rules: rules}
+}
+
+// Adds rules to the linter.
+func (linter *Linter) AddRule(rules ...LintRule) *Linter {
+	linter.rules = append(linter.rules, rules...)
+	return linter
+}
+
+// Runs all rules and returns the issues sorted by descending severity.
+func (linter *Linter) Lint(zoneName string, records []*Record) []*LintIssue {
+	zone := &LintZone{Name: strings.TrimSuffix(zoneName, "."), Records: records}
+	var issues []*LintIssue
+	for _, rule := range linter.rules {
+		for _, issue := range rule.Check(zone) {
+			if issue.Rule == "" {
+				issue.Rule = rule.Name()
+			}
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Severity > issues[j].Severity })
+	return issues
+}
+
+// Returns the built in rules.
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		NewLintRule("cname-at-apex", lintCNAMEAtApex),
+		NewLintRule("cname-with-other-data", lintCNAMEWithOtherData),
+		NewLintRule("target-is-cname", lintTargetIsCNAME),
+		NewLintRule("duplicate-record", lintDuplicateRecord),
+		NewLintRule("rrset-ttl-conflict", lintRRSetTTLConflict),
+		NewLintRule("spf-too-many-lookups", lintSPFLookups),
+		NewLintRule("multiple-spf", lintMultipleSPF),
+		NewLintRule("missing-trailing-dot", lintMissingTrailingDot),
+		NewLintRule("wildcard-shadowing", lintWildcardShadowing),
+	}
+}
+
+func lintCNAMEAtApex(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for _, record := range zone.RecordsAt("@", "CNAME") {
+		issues = append(issues, &LintIssue{Severity: SeverityError, Record: record, Message: "CNAME is not allowed at the zone apex"})
+	}
+	return issues
+}
+
+func lintCNAMEWithOtherData(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for _, record := range zone.Records {
+		if !sameRecordType(record.Type, ptr("CNAME")) {
+			continue
+		}
+		for _, other := range zone.RecordsAt(deref(record.Name), "") {
+			if !sameRecordType(other.Type, ptr("CNAME")) {
+				issues = append(issues, &LintIssue{Severity: SeverityError, Record: record, Message: fmt.Sprintf("CNAME coexists with %s record", deref(other.Type))})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// Returns the host name a record points at for types whose value ends with a target.
+func recordTarget(record *Record) (string, bool) {
+	if !containsFold(hostnameRecordTypes, deref(record.Type)) {
+		return "", false
+	}
+	fields := strings.Fields(deref(record.Value))
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[len(fields)-1], true
+}
+
+func lintTargetIsCNAME(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for _, record := range zone.Records {
+		if !containsFold([]string{"MX", "NS"}, deref(record.Type)) {
+			continue
+		}
+		target, _ := recordTarget(record)
+		if name, ok := zone.RelativeName(target); ok && len(zone.RecordsAt(name, "CNAME")) > 0 {
+			issues = append(issues, &LintIssue{Severity: SeverityError, Record: record, Message: fmt.Sprintf("%s target %s is a CNAME", deref(record.Type), target)})
+		}
+	}
+	return issues
+}
+
+func lintDuplicateRecord(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for i, record := range zone.Records {
+		for _, other := range zone.Records[:i] {
+			if sameRecordName(record.Name, other.Name) && sameRecordType(record.Type, other.Type) && sameRecordValue(record.Type, record.Value, other.Value) {
+				issues = append(issues, &LintIssue{Severity: SeverityWarning, Record: record, Message: "duplicate record"})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+func lintRRSetTTLConflict(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	groups, keys := groupRRSets(zone.Records)
+	for _, key := range keys {
+		records := groups[key]
+		for _, record := range records[1:] {
+			if !sameTTL(record.TTL, records[0].TTL) {
+				issues = append(issues, &LintIssue{Severity: SeverityWarning, Record: records[0], Message: "records of the set have different TTLs"})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+func isSPFRecord(record *Record) bool {
+	return sameRecordType(record.Type, ptr("TXT")) && strings.HasPrefix(strings.ToLower(unquoteTXT(deref(record.Value))), "v=spf1")
+}
+
+func lintSPFLookups(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for _, record := range zone.Records {
+		if !isSPFRecord(record) {
+			continue
+		}
+		spf, err := ParseSPF(deref(record.Value))
+		if err != nil {
+			issues = append(issues, &LintIssue{Severity: SeverityError, Record: record, Message: err.Error()})
+		} else if lookups := spf.Lookups(); lookups > spfLookupLimit {
+			issues = append(issues, &LintIssue{Severity: SeverityError, Record: record, Message: fmt.Sprintf("SPF record needs %d DNS lookups, the limit is %d", lookups, spfLookupLimit)})
+		}
+	}
+	return issues
+}
+
+func lintMultipleSPF(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	seen := map[string]bool{}
+	for _, record := range zone.Records {
+		if !isSPFRecord(record) {
+			continue
+		}
+		name := normalizeRecordName(deref(record.Name))
+		if seen[name] {
+			issues = append(issues, &LintIssue{Severity: SeverityError, Record: record, Message: "multiple SPF records for the same name"})
+		}
+		seen[name] = true
+	}
+	return issues
+}
+
+func lintMissingTrailingDot(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for _, record := range zone.Records {
+		target, ok := recordTarget(record)
+		if !ok || target == "." || strings.HasSuffix(target, ".") || !strings.Contains(target, ".") {
+			continue
+		}
+		issues = append(issues, &LintIssue{Severity: SeverityWarning, Record: record, Message: fmt.Sprintf("target %s has no trailing dot and resolves to %s.%s.", target, target, zone.Name)})
+	}
+	return issues
+}
+
+func lintWildcardShadowing(zone *LintZone) []*LintIssue {
+	var issues []*LintIssue
+	for _, wildcard := range zone.Records {
+		name := normalizeRecordName(deref(wildcard.Name))
+		if !strings.HasPrefix(name, "*") {
+			continue
+		}
+		suffix := strings.TrimPrefix(name, "*")
+		for _, record := range zone.Records {
+			other := normalizeRecordName(deref(record.Name))
+			covered := iif(suffix == "", other != "@", strings.HasSuffix(other, suffix))
+			if !covered || strings.HasPrefix(other, "*") {
+				continue
+			}
+			if len(zone.RecordsAt(other, deref(wildcard.Type))) == 0 && len(zone.RecordsAt(other, "CNAME")) == 0 {
+				issues = append(issues, &LintIssue{Severity: SeverityInfo, Record: wildcard, Message: fmt.Sprintf("%s records at %s shadow the wildcard %s record", deref(record.Type), other, deref(wildcard.Type))})
+				break
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,97 @@
+package gohetznerdns
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func lintRecord(name, recordType, value string, ttl *int) *Record {
+	return &Record{Name: &name, Type: &recordType, Value: &value, TTL: ttl}
+}
+
+func lintRules(issues []*LintIssue) []string {
+	var rules []string
+	for _, issue := range issues {
+		rules = append(rules, issue.Rule)
+	}
+	return rules
+}
+
+func TestLintCleanZone(t *testing.T) {
+	records := []*Record{
+		lintRecord("@", "A", "192.0.2.1", nil),
+		lintRecord("@", "MX", "10 mail.example.com.", nil),
+		lintRecord("mail", "A", "192.0.2.2", nil),
+		lintRecord("www", "CNAME", "example.com.", nil),
+		lintRecord("@", "TXT", `"v=spf1 mx -all"`, nil),
+	}
+	assert.Equal(t, len(NewLinter().Lint("example.com", records)), 0)
+}
+
+func TestLintCNAMERules(t *testing.T) {
+	records := []*Record{
+		lintRecord("@", "CNAME", "cdn.example.net.", nil),
+		lintRecord("www", "CNAME", "example.com.", nil),
+		lintRecord("www", "TXT", "hello", nil),
+		lintRecord("@", "MX", "10 www.example.com.", nil),
+		lintRecord("sub", "NS", "www", nil),
+	}
+	issues := NewLinter().Lint("example.com.", records)
+	assert.DeepEqual(t, lintRules(issues), []string{"cname-at-apex", "cname-with-other-data", "cname-with-other-data", "target-is-cname", "target-is-cname"})
+	assert.Equal(t, issues[0].String(), "error [cname-at-apex] @ CNAME: CNAME is not allowed at the zone apex")
+}
+
+func TestLintDuplicatesAndTTLs(t *testing.T) {
+	records := []*Record{
+		lintRecord("www", "A", "192.0.2.1", ptr(300)),
+		lintRecord("WWW", "A", "192.0.2.1", ptr(300)),
+		lintRecord("www", "A", "192.0.2.2", ptr(600)),
+	}
+	issues := NewLinter().Lint("example.com", records)
+	assert.DeepEqual(t, lintRules(issues), []string{"duplicate-record", "rrset-ttl-conflict"})
+}
+
+func TestLintSPF(t *testing.T) {
+	lookups := "v=spf1 " + strings.Repeat("include:a.example.net ", 10) + "mx -all"
+	records := []*Record{
+		lintRecord("@", "TXT", lookups, nil),
+		lintRecord("@", "TXT", `"v=spf1 -all"`, nil),
+	}
+	issues := NewLinter().Lint("example.com", records)
+	assert.DeepEqual(t, lintRules(issues), []string{"spf-too-many-lookups", "multiple-spf"})
+	assert.Equal(t, issues[0].Message, "SPF record needs 11 DNS lookups, the limit is 10")
+}
+
+func TestLintTrailingDotAndWildcard(t *testing.T) {
+	records := []*Record{
+		lintRecord("www", "CNAME", "cdn.example.net", nil),
+		lintRecord("*", "A", "192.0.2.1", nil),
+		lintRecord("api", "TXT", "token", nil),
+		lintRecord("app", "A", "192.0.2.2", nil),
+	}
+	issues := NewLinter().Lint("example.com", records)
+	assert.DeepEqual(t, lintRules(issues), []string{"missing-trailing-dot", "wildcard-shadowing"})
+	assert.Equal(t, issues[1].Severity.String(), "info")
+	assert.Equal(t, issues[1].Message, "TXT records at api shadow the wildcard A record")
+}
+
+func TestLintCustomRule(t *testing.T) {
+	rule := NewLintRule("max-ttl", func(zone *LintZone) []*LintIssue {
+		var issues []*LintIssue
+		for _, record := range zone.Records {
+			if deref(record.TTL) > 3600 {
+				issues = append(issues, &LintIssue{Severity: SeverityWarning, Record: record, Message: "TTL above policy"})
+			}
+		}
+		return issues
+	})
+	linter := NewLinter(rule)
+	issues := linter.Lint("example.com", []*Record{lintRecord("www", "A", "192.0.2.1", ptr(86400))})
+	assert.DeepEqual(t, lintRules(issues), []string{"max-ttl"})
+
+	linter = NewLinter().AddRule(rule)
+	issues = linter.Lint("example.com", []*Record{lintRecord("@", "CNAME", "x.example.net.", ptr(86400))})
+	assert.DeepEqual(t, lintRules(issues), []string{"cname-at-apex", "max-ttl"})
+}
@@ -0,0 +1,454 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Parameters used to generate the records of a mail provider preset. Providers only use the
+// fields they need, see the preset descriptions.
+type MailPresetParams struct {
+	// Verification token of the provider, e.g. the google-site-verification or MS= value.
+	VerificationCode string
+	// Microsoft 365 tenant name, the prefix of <tenant>.onmicrosoft.com.
+	Tenant string
+	// DKIM selectors, defaults to the provider specific selectors when empty.
+	DKIMSelectors []string
+	// Public DKIM TXT values by selector for providers publishing keys as TXT records.
+	DKIMKeys map[string]string
+	// TTL of the generated records, nil uses the zone default.
+	TTL *int
+}
+
+// Records and SPF include of a mail provider.
+type MailPreset struct {
+	Name string
+	// SPF include added to the apex SPF policy.
+	SPFInclude string
+	// Generates the records of the provider for the domain, excluding SPF.
+	Records func(domain string, params *MailPresetParams) ([]*Record, error)
+	// Reports whether an existing record belongs to the provider.
+	Owns func(domain string, params *MailPresetParams, record *Record) bool
+}
+
+var mailPresets = map[string]*MailPreset{}
+
+// Registers a mail provider preset, replacing any preset with the same name.
+func RegisterMailPreset(preset *MailPreset) {
+	mailPresets[strings.ToLower(preset.Name)] = preset
+}
+
+// Returns the preset registered under the name.
+func GetMailPreset(name string) (*MailPreset, error) {
+	preset, ok := mailPresets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("mail preset %q is not registered", name)
+	}
+	return preset, nil
+}
+
+// Returns the names of the registered presets.
+func MailPresetNames() []string {
+	var names []string
+	for _, preset := range mailPresets {
+		names = append(names, preset.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterMailPreset(googleWorkspacePreset)
+	RegisterMailPreset(microsoft365Preset)
+	RegisterMailPreset(fastmailPreset)
+	RegisterMailPreset(zohoPreset)
+}
+
+func presetParams(params *MailPresetParams) *MailPresetParams {
+	return iif(params == nil, &MailPresetParams{}, params)
+}
+
+func presetRecord(name, recordType, value string, params *MailPresetParams) *Record {
+	return &Record{Name: ptr(name), Type: ptr(recordType), Value: ptr(value), TTL: params.TTL}
+}
+
+func valueHasSuffix(record *Record, suffixes ...string) bool {
+	value := strings.ToLower(deref(record.Value))
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(value, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func dkimSelectors(params *MailPresetParams, defaults ...string) []string {
+	return iif(len(params.DKIMSelectors) > 0, params.DKIMSelectors, defaults)
+}
+
+func isDKIMSelectorRecord(record *Record, selectors []string) bool {
+	for _, selector := range selectors {
+		if sameRecordName(record.Name, ptr(dkimRecordName(selector, "@"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func dkimKeyRecords(params *MailPresetParams, selectors []string) ([]*Record, error) {
+	var records []*Record
+	for _, selector := range selectors {
+		key, ok := params.DKIMKeys[selector]
+		if !ok {
+			continue
+		}
+		if _, err := ParseDKIM(selector, key); err != nil {
+			return nil, err
+		}
+		records = append(records, presetRecord(dkimRecordName(selector, "@"), "TXT", key, params))
+	}
+	return records, nil
+}
+
+// Google Workspace: MX, google-site-verification TXT and DKIM keys from DKIMKeys
+// (selector "google" by default).
+var googleWorkspacePreset = &MailPreset{
+	Name:       "google",
+	SPFInclude: "_spf.google.com",
+	Records: func(domain string, params *MailPresetParams) ([]*Record, error) {
+		records := []*Record{presetRecord("@", "MX", "1 smtp.google.com.", params)}
+		if params.VerificationCode != "" {
+			records = append(records, presetRecord("@", "TXT", "google-site-verification="+params.VerificationCode, params))
+		}
+		keys, err := dkimKeyRecords(params, dkimSelectors(params, "google"))
+		return append(records, keys...), err
+	},
+	Owns: func(domain string, params *MailPresetParams, record *Record) bool {
+		switch normalizeRecordType(deref(record.Type)) {
+		case "MX":
+			return valueHasSuffix(record, "google.com.", "googlemail.com.")
+		case "TXT":
+			return strings.HasPrefix(deref(record.Value), "google-site-verification=") || isDKIMSelectorRecord(record, dkimSelectors(params, "google"))
+		}
+		return false
+	},
+}
+
+func microsoftDomainLabel(domain string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(domain, "."), ".", "-")
+}
+
+// Microsoft 365: MX, autodiscover CNAME, MS= verification TXT and DKIM CNAMEs for selector1
+// and selector2 pointing at the tenant. Requires Tenant.
+var microsoft365Preset = &MailPreset{
+	Name:       "microsoft365",
+	SPFInclude: "spf.protection.outlook.com",
+	Records: func(domain string, params *MailPresetParams) ([]*Record, error) {
+		if params.Tenant == "" {
+			return nil, fmt.Errorf("mail preset microsoft365 requires a tenant")
+		}
+		label := microsoftDomainLabel(domain)
+		records := []*Record{
+			presetRecord("@", "MX", "0 "+label+".mail.protection.outlook.com.", params),
+			presetRecord("autodiscover", "CNAME", "autodiscover.outlook.com.", params),
+		}
+		if params.VerificationCode != "" {
+			records = append(records, presetRecord("@", "TXT", "MS="+params.VerificationCode, params))
+		}
+		for _, selector := range dkimSelectors(params, "selector1", "selector2") {
+			target := fmt.Sprintf("%s-%s._domainkey.%s.onmicrosoft.com.", selector, label, params.Tenant)
+			records = append(records, presetRecord(dkimRecordName(selector, "@"), "CNAME", target, params))
+		}
+		return records, nil
+	},
+	Owns: func(domain string, params *MailPresetParams, record *Record) bool {
+		switch normalizeRecordType(deref(record.Type)) {
+		case "MX":
+			return valueHasSuffix(record, ".mail.protection.outlook.com.")
+		case "CNAME":
+			return valueHasSuffix(record, "outlook.com.", ".onmicrosoft.com.")
+		case "TXT":
+			return strings.HasPrefix(deref(record.Value), "MS=")
+		}
+		return false
+	},
+}
+
+// Fastmail: MX, DKIM CNAMEs fm1 to fm3 and client autodiscovery SRV records.
+var fastmailPreset = &MailPreset{
+	Name:       "fastmail",
+	SPFInclude: "spf.messagingengine.com",
+	Records: func(domain string, params *MailPresetParams) ([]*Record, error) {
+		records := []*Record{
+			presetRecord("@", "MX", "10 in1-smtp.messagingengine.com.", params),
+			presetRecord("@", "MX", "20 in2-smtp.messagingengine.com.", params),
+			presetRecord("_submission._tcp", "SRV", "0 1 587 smtp.fastmail.com.", params),
+			presetRecord("_imaps._tcp", "SRV", "0 1 993 imap.fastmail.com.", params),
+			presetRecord("_carddavs._tcp", "SRV", "0 1 443 carddav.fastmail.com.", params),
+			presetRecord("_caldavs._tcp", "SRV", "0 1 443 caldav.fastmail.com.", params),
+		}
+		for _, selector := range dkimSelectors(params, "fm1", "fm2", "fm3") {
+			target := fmt.Sprintf("%s.%s.dkim.fmhosted.com.", selector, strings.TrimSuffix(domain, "."))
+			records = append(records, presetRecord(dkimRecordName(selector, "@"), "CNAME", target, params))
+		}
+		return records, nil
+	},
+	Owns: func(domain string, params *MailPresetParams, record *Record) bool {
+		return valueHasSuffix(record, ".messagingengine.com.", ".fmhosted.com.", ".fastmail.com.")
+	},
+}
+
+// Zoho Mail: MX, zoho-verification TXT and DKIM keys from DKIMKeys (selector "zmail" by default).
+var zohoPreset = &MailPreset{
+	Name:       "zoho",
+	SPFInclude: "zoho.com",
+	Records: func(domain string, params *MailPresetParams) ([]*Record, error) {
+		records := []*Record{
+			presetRecord("@", "MX", "10 mx.zoho.com.", params),
+			presetRecord("@", "MX", "20 mx2.zoho.com.", params),
+			presetRecord("@", "MX", "50 mx3.zoho.com.", params),
+		}
+		if params.VerificationCode != "" {
+			records = append(records, presetRecord("@", "TXT", "zoho-verification="+params.VerificationCode+".zmverify.zoho.com", params))
+		}
+		keys, err := dkimKeyRecords(params, dkimSelectors(params, "zmail"))
+		return append(records, keys...), err
+	},
+	Owns: func(domain string, params *MailPresetParams, record *Record) bool {
+		switch normalizeRecordType(deref(record.Type)) {
+		case "MX":
+			return valueHasSuffix(record, ".zoho.com.", ".zoho.eu.")
+		case "TXT":
+			return strings.HasPrefix(deref(record.Value), "zoho-verification=") || isDKIMSelectorRecord(record, dkimSelectors(params, "zmail"))
+		}
+		return false
+	},
+}
+
+// Applies mail provider presets to a zone through the record service.
+type MailPresets struct {
+	dns HetznerDNS
+}
+
+// Creates a preset applier for the client.
+func NewMailPresets(dns HetznerDNS) *MailPresets {
+	return &MailPresets{dns: dns.WithoutCache()}
+}
+
+// Returns the names of the registered presets that own at least one of the records.
+func DetectMailPresets(domain string, records []*Record, params *MailPresetParams) []string {
+	params = presetParams(params)
+	var detected []string
+	for _, name := range MailPresetNames() {
+		preset := mailPresets[strings.ToLower(name)]
+		for _, record := range records {
+			if preset.Owns(domain, params, record) {
+				detected = append(detected, preset.Name)
+				break
+			}
+		}
+	}
+	return detected
+}
+
+// Returns the registered presets owning records of the zone.
+func (presets *MailPresets) Detect(zoneId *string, params *MailPresetParams) ([]string, error) {
+	zone, records, err := presets.zoneRecords(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	return DetectMailPresets(deref(zone.Name), records, params), nil
+}
+
+func (presets *MailPresets) zoneRecords(zoneId *string) (*Zone, []*Record, error) {
+	zone, err := presets.dns.GetZoneService().GetZoneById(zoneId)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err := presets.dns.GetRecordService().GetAllRecords(zoneId)
+	return zone, records, err
+}
+
+// Plans the changes that make the zone contain the preset. MX, CNAME and SRV sets and TXT sets
+// below the apex are replaced, apex TXT records are only added and the SPF include is merged
+// into the apex SPF policy.
+func planMailPreset(zoneId *string, domain string, current []*Record, preset *MailPreset, params *MailPresetParams) ([]*RecordChange, error) {
+	params = presetParams(params)
+	desired, err := preset.Records(domain, params)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*RecordChange
+	groups, keys := groupRRSets(desired)
+	for _, key := range keys {
+		key := key
+		var values []string
+		for _, record := range groups[key] {
+			values = append(values, deref(record.Value))
+		}
+		existing := filterRecords(current, &key.name, &key.recordType)
+		if key.recordType == "TXT" && key.name == "@" {
+			existing = nil
+			for _, value := range values {
+				for _, record := range filterRecords(current, &key.name, &key.recordType) {
+					if deref(record.Value) == value {
+						existing = append(existing, record)
+					}
+				}
+			}
+		}
+		changes = append(changes, planRRSetChanges(zoneId, groups[key][0].Name, &key.recordType, existing, values, params.TTL)...)
+	}
+	spfChanges, err := planSPFInclude(zoneId, current, preset.SPFInclude, true, params.TTL)
+	return append(changes, spfChanges...), err
+}
+
+// Plans adding or removing an include in the apex SPF policy. A policy left without
+// mechanisms other than all is deleted.
+func planSPFInclude(zoneId *string, current []*Record, include string, add bool, ttl *int) ([]*RecordChange, error) {
+	if include == "" {
+		return nil, nil
+	}
+	var existing *Record
+	for _, record := range filterRecords(current, ptr("@"), ptr("TXT")) {
+		if isSPFRecord(record) {
+			existing = record
+			break
+		}
+	}
+	if existing == nil {
+		if !add {
+			return nil, nil
+		}
+		spf := &SPF{Mechanisms: []*SPFMechanism{{Qualifier: SPFPass, Kind: "include", Value: include}, {Qualifier: SPFSoftFail, Kind: "all"}}}
+		return planRRSetChanges(zoneId, ptr("@"), ptr("TXT"), nil, []string{spf.String()}, ttl), nil
+	}
+	spf, err := ParseSPF(deref(existing.Value))
+	if err != nil {
+		return nil, err
+	}
+	var mechanisms []*SPFMechanism
+	found := false
+	for _, mechanism := range spf.Mechanisms {
+		if mechanism.Kind == "include" && strings.EqualFold(mechanism.Value, include) {
+			found = true
+			if !add {
+				continue
+			}
+		}
+		mechanisms = append(mechanisms, mechanism)
+	}
+	if found == add {
+		return nil, nil
+	}
+	if add {
+		position := len(mechanisms)
+		if position > 0 && mechanisms[position-1].Kind == "all" {
+			position--
+		}
+		mechanisms = append(mechanisms[:position], append([]*SPFMechanism{{Qualifier: SPFPass, Kind: "include", Value: include}}, mechanisms[position:]...)...)
+	}
+	spf.Mechanisms = mechanisms
+	if len(mechanisms) == 0 || (len(mechanisms) == 1 && mechanisms[0].Kind == "all" && spf.Redirect == "") {
+		return []*RecordChange{{Action: ChangeDelete, Before: existing}}, nil
+	}
+	return planRRSetChanges(zoneId, ptr("@"), ptr("TXT"), []*Record{existing}, []string{spf.String()}, nil), nil
+}
+
+// Plans removing the records owned by the preset and its SPF include. Records also generated by
+// the keep preset, if given, are left in place.
+func planMailPresetRemoval(zoneId *string, domain string, current []*Record, preset *MailPreset, params *MailPresetParams, keep *MailPreset, keepParams *MailPresetParams) ([]*RecordChange, error) {
+	params, keepParams = presetParams(params), presetParams(keepParams)
+	var kept []*Record
+	if keep != nil {
+		var err error
+		if kept, err = keep.Records(domain, keepParams); err != nil {
+			return nil, err
+		}
+	}
+	var changes []*RecordChange
+	for _, record := range current {
+		if isSPFRecord(record) || !preset.Owns(domain, params, record) {
+			continue
+		}
+		if keep != nil && keep.Owns(domain, keepParams, record) {
+			continue
+		}
+		wanted := false
+		for _, other := range kept {
+			wanted = wanted || (sameRecordName(record.Name, other.Name) && sameRecordType(record.Type, other.Type) && sameRecordValue(record.Type, record.Value, other.Value))
+		}
+		if !wanted {
+			changes = append(changes, &RecordChange{Action: ChangeDelete, Before: record})
+		}
+	}
+	if keep != nil && strings.EqualFold(keep.SPFInclude, preset.SPFInclude) {
+		return changes, nil
+	}
+	spfChanges, err := planSPFInclude(zoneId, current, preset.SPFInclude, false, nil)
+	return append(changes, spfChanges...), err
+}
+
+// Creates or updates the records of the preset in the zone. Running it again makes no changes.
+func (presets *MailPresets) Apply(zoneId *string, provider string, params *MailPresetParams) (*ChangeSummary, error) {
+	preset, err := GetMailPreset(provider)
+	if err != nil {
+		return nil, err
+	}
+	zone, current, err := presets.zoneRecords(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := planMailPreset(zoneId, deref(zone.Name), current, preset, params)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := applyRecordChanges(presets.dns.GetRecordService(), changes)
+	return newChangeSummary(applied), err
+}
+
+// Removes the records and SPF include of the preset from the zone.
+func (presets *MailPresets) Remove(zoneId *string, provider string, params *MailPresetParams) (*ChangeSummary, error) {
+	preset, err := GetMailPreset(provider)
+	if err != nil {
+		return nil, err
+	}
+	zone, current, err := presets.zoneRecords(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := planMailPresetRemoval(zoneId, deref(zone.Name), current, preset, params, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := applyRecordChanges(presets.dns.GetRecordService(), changes)
+	return newChangeSummary(applied), err
+}
+
+// Switches the zone from one provider to another: the new preset is applied first, then the
+// records of the old provider that the new one does not use are removed.
+func (presets *MailPresets) Switch(zoneId *string, from string, fromParams *MailPresetParams, to string, toParams *MailPresetParams) (*ChangeSummary, error) {
+	oldPreset, err := GetMailPreset(from)
+	if err != nil {
+		return nil, err
+	}
+	newPreset, err := GetMailPreset(to)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := presets.Apply(zoneId, to, toParams)
+	if err != nil {
+		return applied, err
+	}
+	zone, current, err := presets.zoneRecords(zoneId)
+	if err != nil {
+		return applied, err
+	}
+	changes, err := planMailPresetRemoval(zoneId, deref(zone.Name), current, oldPreset, fromParams, newPreset, toParams)
+	if err != nil {
+		return applied, err
+	}
+	removed, err := applyRecordChanges(presets.dns.GetRecordService(), changes)
+	return newChangeSummary(append(applied.Changes, removed...)), err
+}
@@ -0,0 +1,103 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func recordValues(records []*Record, name, recordType string) []string {
+	var values []string
+	for _, record := range filterRecords(records, &name, &recordType) {
+		values = append(values, *record.Value)
+	}
+	return values
+}
+
+func TestMailPresetNames(t *testing.T) {
+	assert.DeepEqual(t, MailPresetNames(), []string{"fastmail", "google", "microsoft365", "zoho"})
+	_, err := GetMailPreset("unknown")
+	assert.Error(t, err, "mail preset \"unknown\" is not registered")
+}
+
+func TestApplyMailPresetIsIdempotent(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "@", "TXT", "v=spf1 ip4:192.0.2.1 -all", nil)
+	api.addRecord(*zone.Id, "@", "TXT", "other-verification=1", nil)
+	presets := NewMailPresets(api.dns(t))
+	params := &MailPresetParams{Tenant: "contoso", VerificationCode: "ms123"}
+
+	summary, err := presets.Apply(zone.Id, "microsoft365", params)
+	assert.NilError(t, err)
+	assert.Equal(t, summary.Created, 5)
+	assert.Equal(t, summary.Updated, 1)
+
+	records := api.zoneRecords(*zone.Id)
+	assert.DeepEqual(t, recordValues(records, "@", "MX"), []string{"0 example-com.mail.protection.outlook.com."})
+	assert.DeepEqual(t, recordValues(records, "selector1._domainkey", "CNAME"), []string{"selector1-example-com._domainkey.contoso.onmicrosoft.com."})
+	assert.DeepEqual(t, recordValues(records, "@", "TXT"), []string{"MS=ms123", "other-verification=1", "v=spf1 ip4:192.0.2.1 include:spf.protection.outlook.com -all"})
+
+	summary, err = presets.Apply(zone.Id, "microsoft365", params)
+	assert.NilError(t, err)
+	assert.Equal(t, len(summary.Changes), 0)
+
+	detected, err := presets.Detect(zone.Id, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, detected, []string{"microsoft365"})
+}
+
+func TestApplyMailPresetRequiresTenant(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	_, err := NewMailPresets(api.dns(t)).Apply(zone.Id, "microsoft365", nil)
+	assert.Error(t, err, "mail preset microsoft365 requires a tenant")
+}
+
+func TestSwitchMailPreset(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	presets := NewMailPresets(api.dns(t))
+	googleParams := &MailPresetParams{VerificationCode: "abc", DKIMKeys: map[string]string{"google": "v=DKIM1; k=rsa; p=AAAA"}}
+	_, err := presets.Apply(zone.Id, "google", googleParams)
+	assert.NilError(t, err)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+
+	_, err = presets.Switch(zone.Id, "google", googleParams, "fastmail", nil)
+	assert.NilError(t, err)
+	records := api.zoneRecords(*zone.Id)
+	assert.DeepEqual(t, recordValues(records, "@", "MX"), []string{"10 in1-smtp.messagingengine.com.", "20 in2-smtp.messagingengine.com."})
+	assert.DeepEqual(t, recordValues(records, "@", "TXT"), []string{"v=spf1 include:spf.messagingengine.com ~all"})
+	assert.Equal(t, len(recordValues(records, "google._domainkey", "TXT")), 0)
+	assert.Equal(t, len(recordValues(records, "www", "A")), 1)
+	assert.DeepEqual(t, DetectMailPresets("example.com", records, nil), []string{"fastmail"})
+}
+
+func TestRemoveMailPreset(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	presets := NewMailPresets(api.dns(t))
+	_, err := presets.Apply(zone.Id, "zoho", &MailPresetParams{VerificationCode: "z1"})
+	assert.NilError(t, err)
+
+	summary, err := presets.Remove(zone.Id, "zoho", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, summary.Deleted, 5)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 0)
+}
+
+func TestPlanSPFInclude(t *testing.T) {
+	spf := &Record{Id: ptr("1"), Name: ptr("@"), Type: ptr("TXT"), Value: ptr("v=spf1 include:a.example.net include:b.example.net ~all")}
+	changes, err := planSPFInclude(ptr("zone"), []*Record{spf}, "b.example.net", true, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 0)
+
+	changes, err = planSPFInclude(ptr("zone"), []*Record{spf}, "a.example.net", false, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, *changes[0].After.Value, "v=spf1 include:b.example.net ~all")
+
+	single := &Record{Id: ptr("1"), Name: ptr("@"), Type: ptr("TXT"), Value: ptr("v=spf1 include:a.example.net ~all")}
+	changes, err = planSPFInclude(ptr("zone"), []*Record{single}, "a.example.net", false, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, changes[0].Action, ChangeDelete)
+}
@@ -7,6 +7,10 @@ type RecordService interface {
 	// Returns all records associated with user. [https://dns.hetzner.com/api-docs#operation/GetRecords]
 	GetAllRecords(zone_id *string) ([]*Record, error)
 
+	// Returns the records of every zone, fetching up to concurrency zones at once, defaults to 4.
+	// The results follow the order of the zone ids and carry the error of each zone.
+	GetRecordsForZones(zone_ids []*string, concurrency int) []*BatchResult[*string, []*Record]
+
 	//Returns information about a single record. [https://dns.hetzner.com/api-docs#operation/GetRecord]
 	GetRecord(record_id *string) (*Record, error)
 
@@ -18,10 +22,35 @@ type RecordService interface {
 
 	//Deletes a record. [https://dns.hetzner.com/api-docs#operation/DeleteRecord]
 	DeleteRecord(record_id *string) error
+
+	// Ensures a record with the given name, type and value exists in the zone, creating or
+	// updating it only when needed. See [EnsureResult] for the matching rules.
+	EnsureRecord(zone_id, name, record_type, value *string, ttl *int) (*Record, EnsureResult, error)
+
+	// Ensures no record with the given name and type exists in the zone. When value is not nil
+	// only records with that value are removed.
+	EnsureAbsent(zone_id, name, record_type, value *string) (EnsureResult, error)
+
+	// Returns all records of the given name and type in the zone.
+	GetRRSet(zone_id, name, record_type *string) (*RRSet, error)
+
+	// Replaces the records of the given name and type with the given values using the minimal
+	// set of changes. Creates run before deletes, and applied changes are reverted on failure.
+	ReplaceRRSet(zone_id, name, record_type *string, values []string, ttl *int) (*RRSet, error)
+
+	// Deletes all records of the given name and type in the zone.
+	DeleteRRSet(zone_id, name, record_type *string) error
 }
 
 type recordService struct {
 	client *client
+	// Reads skip the cache, see [HetznerDNS.WithoutCache].
+	bypassCache bool
+}
+
+// Returns the service reading past the cache, used by reads that writes are based on.
+func (service *recordService) fresh() *recordService {
+	return &recordService{client: service.client, bypassCache: true}
 }
 
 func (service *recordService) GetAllRecords(zone_id *string) ([]*Record, error) {
@@ -36,10 +65,14 @@ func (service *recordService) GetAllRecords(zone_id *string) ([]*Record, error)
 				"zone_id": *zone_id,
 			}).
 		setResult(records).
+		setBypassCache(service.bypassCache).
 		execute("GET", recordsBasePath)
 	if err != nil {
 		return nil, err
 	}
+	for _, record := range records.Records {
+		decodeTXTRecord(record)
+	}
 	return records.Records, nil
 }
 func (service *recordService) GetRecord(record_id *string) (*Record, error) {
@@ -50,11 +83,12 @@ func (service *recordService) GetRecord(record_id *string) (*Record, error) {
 	_, err := service.client.
 		createJsonRequest(200).
 		setResult(record).
+		setBypassCache(service.bypassCache).
 		execute("GET", recordsBasePath+"/"+*record_id)
 	if err != nil {
 		return nil, err
 	}
-	return record.Record, nil
+	return decodeTXTRecord(record.Record), nil
 }
 
 func (service *recordService) CreateRecord(request *Record) (*Record, error) {
@@ -62,37 +96,56 @@ func (service *recordService) CreateRecord(request *Record) (*Record, error) {
 	_, err := service.client.
 		createJsonRequest(200).
 		setResult(record).
-		setBody(request).
+		setBody(encodeTXTRecord(request)).
 		execute("POST", recordsBasePath)
 	if err != nil {
 		return nil, err
 	}
-	return record.Record, nil
+	created := decodeTXTRecord(record.Record)
+	return created, service.client.writeJournal(&JournalEntry{Operation: OperationCreateRecord, RecordAfter: created})
 }
 
 func (service *recordService) UpdateRecord(request *Record) (*Record, error) {
 	if err := validateNotEmpty("record_id", request.Id); err != nil {
 		return nil, err
 	}
+	var before *Record
+	if service.client.journaling() {
+		var err error
+		if before, err = service.fresh().GetRecord(request.Id); err != nil {
+			return nil, err
+		}
+	}
 	record := new(RecordResponse)
 	_, err := service.client.
 		createJsonRequest(200).
 		setResult(record).
-		setBody(request).
+		setBody(encodeTXTRecord(request)).
 		execute("PUT", recordsBasePath+"/"+*request.Id)
 	if err != nil {
 		return nil, err
 	}
-	return record.Record, nil
+	updated := decodeTXTRecord(record.Record)
+	return updated, service.client.writeJournal(&JournalEntry{Operation: OperationUpdateRecord, RecordBefore: before, RecordAfter: updated})
 }
 
 func (service *recordService) DeleteRecord(record_id *string) error {
 	if err := validateNotEmpty("record_id", record_id); err != nil {
 		return err
 	}
+	var before *Record
+	if service.client.journaling() {
+		var err error
+		// A record that is not found is already gone, the delete is a no-op then.
+		if before, err = service.fresh().GetRecord(record_id); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
 	_, err := service.client.
 		createTextRequest(200, 404).
 		execute("DELETE", recordsBasePath+"/"+*record_id)
-
-	return err
+	if err != nil || before == nil {
+		return err
+	}
+	return service.client.writeJournal(&JournalEntry{Operation: OperationDeleteRecord, RecordBefore: before})
 }
@@ -0,0 +1,207 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Mapping of old addresses or networks to new ones, used to move A and AAAA records and SPF
+// ip4/ip6 mechanisms to new addresses. Networks map to networks of the same size keeping the
+// host part, e.g. 192.0.2.10 in 192.0.2.0/24 -> 198.51.100.0/24 becomes 198.51.100.10.
+type Renumbering struct {
+	rules []*renumberRule
+}
+
+type renumberRule struct {
+	from, to *net.IPNet
+}
+
+func parseRenumberNetwork(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		return network, err
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address %q", value)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Creates a renumbering from old to new addresses or networks. Both sides of a mapping must
+// be of the same family and size. Overlapping old networks are resolved by the most specific.
+func NewRenumbering(mapping map[string]string) (*Renumbering, error) {
+	renumbering := &Renumbering{}
+	for from, to := range mapping {
+		fromNetwork, err := parseRenumberNetwork(from)
+		if err != nil {
+			return nil, fmt.Errorf("renumber: %w", err)
+		}
+		toNetwork, err := parseRenumberNetwork(to)
+		if err != nil {
+			return nil, fmt.Errorf("renumber: %w", err)
+		}
+		fromOnes, fromBits := fromNetwork.Mask.Size()
+		toOnes, toBits := toNetwork.Mask.Size()
+		if fromOnes != toOnes || fromBits != toBits {
+			return nil, fmt.Errorf("renumber: %s and %s differ in family or size", from, to)
+		}
+		renumbering.rules = append(renumbering.rules, &renumberRule{from: fromNetwork, to: toNetwork})
+	}
+	sort.Slice(renumbering.rules, func(i, j int) bool {
+		a, _ := renumbering.rules[i].from.Mask.Size()
+		b, _ := renumbering.rules[j].from.Mask.Size()
+		return a > b || (a == b && renumbering.rules[i].from.String() < renumbering.rules[j].from.String())
+	})
+	return renumbering, nil
+}
+
+// Returns the renumbering from the new addresses back to the old ones.
+func (renumbering *Renumbering) Reverse() *Renumbering {
+	reverse := &Renumbering{}
+	for _, rule := range renumbering.rules {
+		reverse.rules = append(reverse.rules, &renumberRule{from: rule.to, to: rule.from})
+	}
+	return reverse
+}
+
+// Returns the new address of the given one, or false when no mapping covers it.
+func (renumbering *Renumbering) Map(ip net.IP) (net.IP, bool) {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	network, ok := renumbering.mapNetwork(&net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+	if !ok {
+		return nil, false
+	}
+	return network.IP, true
+}
+
+// Maps a network that lies completely inside an old network.
+func (renumbering *Renumbering) mapNetwork(network *net.IPNet) (*net.IPNet, bool) {
+	ip := network.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	ones, _ := network.Mask.Size()
+	for _, rule := range renumbering.rules {
+		ruleOnes, bits := rule.from.Mask.Size()
+		if bits != len(ip)*8 || ones < ruleOnes || !rule.from.Contains(ip) {
+			continue
+		}
+		mapped := make(net.IP, len(ip))
+		for i := range ip {
+			mapped[i] = rule.to.IP[i] | ip[i]&^rule.from.Mask[i]
+		}
+		return &net.IPNet{IP: mapped, Mask: net.CIDRMask(ones, bits)}, true
+	}
+	return nil, false
+}
+
+// Returns the record value after renumbering, or false when the value is unaffected.
+func (renumbering *Renumbering) renumberRecord(record *Record) (string, bool) {
+	value := deref(record.Value)
+	switch {
+	case containsFold([]string{"A", "AAAA"}, deref(record.Type)):
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", false
+		}
+		mapped, ok := renumbering.Map(ip)
+		if !ok {
+			return "", false
+		}
+		return mapped.String(), true
+	case isSPFRecord(record):
+		spf, err := ParseSPF(value)
+		if err != nil {
+			return "", false
+		}
+		changed := false
+		for _, mechanism := range spf.Mechanisms {
+			if mechanism.Kind != "ip4" && mechanism.Kind != "ip6" {
+				continue
+			}
+			network, err := parseRenumberNetwork(mechanism.Value)
+			if err != nil {
+				continue
+			}
+			mapped, ok := renumbering.mapNetwork(network)
+			if !ok {
+				continue
+			}
+			ones, bits := mapped.Mask.Size()
+			mechanism.Value = mapped.IP.String() + iif(ones == bits && !strings.Contains(mechanism.Value, "/"), "", fmt.Sprintf("/%d", ones))
+			changed = true
+		}
+		return spf.String(), changed
+	}
+	return "", false
+}
+
+// Record update of a [RenumberPlan] and the zone of the record.
+type RenumberChange struct {
+	Zone   *Zone
+	Change *RecordChange
+}
+
+func (change *RenumberChange) String() string {
+	return deref(change.Zone.Name) + ": " + change.Change.String()
+}
+
+// Reviewable list of record updates that apply a renumbering.
+type RenumberPlan struct {
+	Changes []*RenumberChange
+}
+
+func (plan *RenumberPlan) String() string {
+	lines := make([]string, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Finds the A, AAAA and SPF records of all zones affected by the renumbering and returns the
+// updates that move them to the new addresses. Nothing is changed.
+func PlanRenumbering(dns HetznerDNS, renumbering *Renumbering) (*RenumberPlan, error) {
+	if err := validateNotNil("renumbering", renumbering); err != nil {
+		return nil, err
+	}
+	matches, err := SearchRecords(dns.WithoutCache(), &SearchQuery{Types: []string{"A", "AAAA", "TXT"}})
+	if err != nil {
+		return nil, err
+	}
+	plan := &RenumberPlan{}
+	for _, match := range matches {
+		value, ok := renumbering.renumberRecord(match.Record)
+		if !ok {
+			continue
+		}
+		after := copyRecord(match.Record, true)
+		after.Value = &value
+		plan.Changes = append(plan.Changes, &RenumberChange{
+			Zone:   match.Zone,
+			Change: &RecordChange{Action: ChangeUpdate, Before: match.Record, After: after},
+		})
+	}
+	return plan, nil
+}
+
+// Applies the plan through UpdateRecord and returns the applied changes. Changes applied before
+// a failure stay in place; they can be rolled back by planning the reversed renumbering.
+func ApplyRenumbering(dns HetznerDNS, plan *RenumberPlan) ([]*RecordChange, error) {
+	if err := validateNotNil("plan", plan); err != nil {
+		return nil, err
+	}
+	changes := make([]*RecordChange, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		changes = append(changes, change.Change)
+	}
+	return applyRecordChanges(dns.GetRecordService(), changes)
+}
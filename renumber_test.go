@@ -0,0 +1,77 @@
+package gohetznerdns
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestRenumberingMap(t *testing.T) {
+	renumbering, err := NewRenumbering(map[string]string{
+		"192.0.2.0/24":  "198.51.100.0/24",
+		"192.0.2.10":    "203.0.113.1",
+		"2001:db8::/64": "2001:db8:1::/64",
+	})
+	assert.NilError(t, err)
+	mapped, ok := renumbering.Map(net.ParseIP("192.0.2.25"))
+	assert.Assert(t, ok)
+	assert.Equal(t, mapped.String(), "198.51.100.25")
+	mapped, _ = renumbering.Map(net.ParseIP("192.0.2.10"))
+	assert.Equal(t, mapped.String(), "203.0.113.1")
+	mapped, _ = renumbering.Map(net.ParseIP("2001:db8::5"))
+	assert.Equal(t, mapped.String(), "2001:db8:1::5")
+	_, ok = renumbering.Map(net.ParseIP("192.0.3.1"))
+	assert.Assert(t, !ok)
+	mapped, _ = renumbering.Reverse().Map(net.ParseIP("198.51.100.25"))
+	assert.Equal(t, mapped.String(), "192.0.2.25")
+
+	_, err = NewRenumbering(map[string]string{"192.0.2.0/24": "198.51.100.0/25"})
+	assert.ErrorContains(t, err, "differ in family or size")
+	_, err = NewRenumbering(map[string]string{"192.0.2.1": "2001:db8::1"})
+	assert.ErrorContains(t, err, "differ in family or size")
+}
+
+func TestRenumberSPFWithModifiers(t *testing.T) {
+	renumbering, err := NewRenumbering(map[string]string{"192.0.2.0/24": "198.51.100.0/24"})
+	assert.NilError(t, err)
+	value, ok := renumbering.renumberRecord(&Record{Type: ptr("TXT"), Value: ptr("v=spf1 ip4:192.0.2.0/28 ra=postmaster -all")})
+	assert.Assert(t, ok)
+	assert.Equal(t, value, "v=spf1 ip4:198.51.100.0/28 -all ra=postmaster")
+}
+
+func TestRenumber(t *testing.T) {
+	api := newFakeAPI(t)
+	com := api.addZone("example.com", 3600)
+	org := api.addZone("example.org", 3600)
+	api.addRecord(*com.Id, "www", "A", "192.0.2.7", nil)
+	api.addRecord(*com.Id, "@", "TXT", "v=spf1 ip4:192.0.2.0/25 ip4:192.0.2.7 ip4:192.0.0.0/16 -all", nil)
+	api.addRecord(*com.Id, "other", "A", "192.0.3.7", nil)
+	api.addRecord(*org.Id, "v6", "AAAA", "2001:db8::7", ptr(300))
+	dns := api.dns(t)
+	renumbering, err := NewRenumbering(map[string]string{
+		"192.0.2.0/24":  "198.51.100.0/24",
+		"2001:db8::/64": "2001:db8:1::/64",
+	})
+	assert.NilError(t, err)
+
+	plan, err := PlanRenumbering(dns, renumbering)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.String(), `example.com: update @ TXT v=spf1 ip4:192.0.2.0/25 ip4:192.0.2.7 ip4:192.0.0.0/16 -all -> v=spf1 ip4:198.51.100.0/25 ip4:198.51.100.7 ip4:192.0.0.0/16 -all
+example.com: update www A 192.0.2.7 -> 198.51.100.7
+example.org: update v6 AAAA 2001:db8::7 -> 2001:db8:1::7`)
+
+	applied, err := ApplyRenumbering(dns, plan)
+	assert.NilError(t, err)
+	assert.Equal(t, len(applied), 3)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*com.Id), "www", "A"), []string{"198.51.100.7"})
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*com.Id), "other", "A"), []string{"192.0.3.7"})
+	assert.Equal(t, *api.zoneRecords(*org.Id)[0].TTL, 300)
+
+	plan, err = PlanRenumbering(dns, renumbering.Reverse())
+	assert.NilError(t, err)
+	_, err = ApplyRenumbering(dns, plan)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*com.Id), "@", "TXT"), []string{"v=spf1 ip4:192.0.2.0/25 ip4:192.0.2.7 ip4:192.0.0.0/16 -all"})
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*org.Id), "v6", "AAAA"), []string{"2001:db8::7"})
+}
@@ -0,0 +1,208 @@
+package gohetznerdns
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Set of records sharing zone, name and type.
+type RRSet struct {
+	ZoneId  *string
+	Name    *string
+	Type    *string
+	Records []*Record
+}
+
+// Returns the values of the records in the set.
+func (rrset *RRSet) Values() []string {
+	values := make([]string, 0, len(rrset.Records))
+	for _, record := range rrset.Records {
+		values = append(values, deref(record.Value))
+	}
+	return values
+}
+
+// Kind of a single record mutation.
+type ChangeAction string
+
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// A single record mutation. Before is nil for creates and After is nil for deletes.
+type RecordChange struct {
+	Action ChangeAction `json:"action"`
+	Before *Record      `json:"before,omitempty"`
+	After  *Record      `json:"after,omitempty"`
+}
+
+// Returns the change that undoes this one.
+func (change *RecordChange) Inverse() *RecordChange {
+	switch change.Action {
+	case ChangeCreate:
+		return &RecordChange{Action: ChangeDelete, Before: change.After}
+	case ChangeDelete:
+		return &RecordChange{Action: ChangeCreate, After: copyRecord(change.Before, false)}
+	default:
+		return &RecordChange{Action: ChangeUpdate, Before: change.After, After: change.Before}
+	}
+}
+
+func (change *RecordChange) String() string {
+	record := iif(change.After != nil, change.After, change.Before)
+	switch change.Action {
+	case ChangeUpdate:
+		return fmt.Sprintf("%s %s %s %s -> %s", change.Action, deref(record.Name), deref(record.Type), deref(change.Before.Value), deref(change.After.Value))
+	default:
+		return fmt.Sprintf("%s %s %s %s", change.Action, deref(record.Name), deref(record.Type), deref(record.Value))
+	}
+}
+
+func copyRecord(record *Record, keepId bool) *Record {
+	copy := &Record{
+		Type:   record.Type,
+		ZoneId: record.ZoneId,
+		Name:   record.Name,
+		Value:  record.Value,
+		TTL:    record.TTL,
+	}
+	if keepId {
+		copy.Id = record.Id
+	}
+	return copy
+}
+
+// Applies changes in order and returns the ones that succeeded. Created and updated
+// records are written back into the After field of the change.
+func applyRecordChanges(service RecordService, changes []*RecordChange) ([]*RecordChange, error) {
+	var applied []*RecordChange
+	for _, change := range changes {
+		var err error
+		var record *Record
+		switch change.Action {
+		case ChangeCreate:
+			record, err = service.CreateRecord(copyRecord(change.After, false))
+		case ChangeUpdate:
+			update := copyRecord(change.After, false)
+			update.Id = change.Before.Id
+			record, err = service.UpdateRecord(update)
+		case ChangeDelete:
+			err = service.DeleteRecord(change.Before.Id)
+		}
+		if err != nil && !isJournalError(err) {
+			return applied, fmt.Errorf("%s: %w", change, err)
+		}
+		if record != nil {
+			change.After = record
+		}
+		// A journal failure follows a successful call, the change still needs to be undone on rollback.
+		applied = append(applied, change)
+		if err != nil {
+			return applied, fmt.Errorf("%s: %w", change, err)
+		}
+	}
+	return applied, nil
+}
+
+// Undoes applied changes in reverse order and returns the ones that could not be undone.
+func revertRecordChanges(service RecordService, applied []*RecordChange) ([]*RecordChange, error) {
+	var failed []*RecordChange
+	var errs []error
+	for i := len(applied) - 1; i >= 0; i-- {
+		if _, err := applyRecordChanges(service, []*RecordChange{applied[i].Inverse()}); err != nil {
+			failed = append(failed, applied[i])
+			errs = append(errs, err)
+		}
+	}
+	return failed, errors.Join(errs...)
+}
+
+// Computes the minimal changes turning current into the desired values. Records whose value is
+// kept stay untouched unless the TTL differs, surplus records are reused for new values and the
+// result is ordered creates, updates, deletes.
+func planRRSetChanges(zone_id, name, record_type *string, current []*Record, values []string, ttl *int) []*RecordChange {
+	var creates, updates, deletes []*RecordChange
+	remaining := append([]*Record{}, current...)
+	var missing []string
+	for _, value := range values {
+		found := -1
+		for i, record := range remaining {
+			if sameRecordValue(record_type, record.Value, &value) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			missing = append(missing, value)
+			continue
+		}
+		record := remaining[found]
+		remaining = append(remaining[:found], remaining[found+1:]...)
+		if ttl != nil && !sameTTL(record.TTL, ttl) {
+			after := copyRecord(record, true)
+			after.TTL = ttl
+			updates = append(updates, &RecordChange{Action: ChangeUpdate, Before: record, After: after})
+		}
+	}
+	for _, value := range missing {
+		value := value
+		if len(remaining) > 0 {
+			record := remaining[0]
+			remaining = remaining[1:]
+			after := copyRecord(record, true)
+			after.Value = &value
+			after.TTL = iif(ttl == nil, record.TTL, ttl)
+			updates = append(updates, &RecordChange{Action: ChangeUpdate, Before: record, After: after})
+			continue
+		}
+		creates = append(creates, &RecordChange{
+			Action: ChangeCreate,
+			After:  &Record{ZoneId: zone_id, Name: name, Type: record_type, Value: &value, TTL: ttl},
+		})
+	}
+	for _, record := range remaining {
+		deletes = append(deletes, &RecordChange{Action: ChangeDelete, Before: record})
+	}
+	return append(append(creates, updates...), deletes...)
+}
+
+func (service *recordService) GetRRSet(zone_id, name, record_type *string) (*RRSet, error) {
+	if err := validateNotEmpty("name", name); err != nil {
+		return nil, err
+	}
+	if err := validateNotEmpty("record_type", record_type); err != nil {
+		return nil, err
+	}
+	records, err := service.GetAllRecords(zone_id)
+	if err != nil {
+		return nil, err
+	}
+	return &RRSet{ZoneId: zone_id, Name: name, Type: record_type, Records: filterRecords(records, name, record_type)}, nil
+}
+
+func (service *recordService) ReplaceRRSet(zone_id, name, record_type *string, values []string, ttl *int) (*RRSet, error) {
+	rrset, err := service.fresh().GetRRSet(zone_id, name, record_type)
+	if err != nil {
+		return nil, err
+	}
+	changes := planRRSetChanges(zone_id, name, record_type, rrset.Records, values, ttl)
+	applied, err := applyRecordChanges(service, changes)
+	if err != nil {
+		if _, revertErr := revertRecordChanges(service, applied); revertErr != nil {
+			return nil, fmt.Errorf("%w; restoring original records failed: %w", err, revertErr)
+		}
+		return nil, err
+	}
+	return service.GetRRSet(zone_id, name, record_type)
+}
+
+func (service *recordService) DeleteRRSet(zone_id, name, record_type *string) error {
+	rrset, err := service.fresh().GetRRSet(zone_id, name, record_type)
+	if err != nil {
+		return err
+	}
+	_, err = applyRecordChanges(service, planRRSetChanges(zone_id, name, record_type, rrset.Records, nil, nil))
+	return err
+}
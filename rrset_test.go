@@ -0,0 +1,126 @@
+package gohetznerdns
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func sortedValues(rrset *RRSet) []string {
+	values := rrset.Values()
+	sort.Strings(values)
+	return values
+}
+
+func TestGetRRSet(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", nil)
+	api.addRecord(*zone.Id, "www", "AAAA", "2001:db8::1", nil)
+	api.addRecord(*zone.Id, "mail", "A", "192.0.2.3", nil)
+
+	rrset, err := api.dns(t).GetRecordService().GetRRSet(zone.Id, ptr("www"), ptr("A"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sortedValues(rrset), []string{"192.0.2.1", "192.0.2.2"})
+}
+
+func TestReplaceRRSetMinimalChanges(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	kept := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", nil)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.3", nil)
+	service := api.dns(t).GetRecordService()
+
+	rrset, err := service.ReplaceRRSet(zone.Id, ptr("www"), ptr("A"), []string{"192.0.2.1", "192.0.2.9"}, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sortedValues(rrset), []string{"192.0.2.1", "192.0.2.9"})
+	assert.Equal(t, api.callCount("POST"), 0)
+	assert.Equal(t, api.callCount("PUT"), 1)
+	assert.Equal(t, api.callCount("DELETE"), 1)
+	for _, record := range rrset.Records {
+		if *record.Value == "192.0.2.1" {
+			assert.Equal(t, *record.Id, *kept.Id)
+		}
+	}
+}
+
+func TestReplaceRRSetCreatesBeforeDeletes(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	service := api.dns(t).GetRecordService()
+
+	_, err := service.ReplaceRRSet(zone.Id, ptr("www"), ptr("A"), []string{"192.0.2.7", "192.0.2.8", "192.0.2.9"}, ptr(60))
+	assert.NilError(t, err)
+	var mutations []string
+	for _, call := range api.calls {
+		if !strings.HasPrefix(call, "GET") {
+			mutations = append(mutations, strings.Fields(call)[0])
+		}
+	}
+	assert.DeepEqual(t, mutations, []string{"POST", "POST", "PUT"})
+	for _, record := range api.zoneRecords(*zone.Id) {
+		assert.Equal(t, *record.TTL, 60)
+	}
+}
+
+func TestReplaceRRSetRestoresOnFailure(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", nil)
+	api.failOn = func(method, path string, body []byte) bool {
+		return method == "DELETE"
+	}
+	service := api.dns(t).GetRecordService()
+
+	_, err := service.ReplaceRRSet(zone.Id, ptr("www"), ptr("A"), []string{"192.0.2.5"}, nil)
+	assert.ErrorContains(t, err, "delete www A")
+	records := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, *records[0].Value, "192.0.2.1")
+	assert.Equal(t, *records[1].Value, "192.0.2.2")
+}
+
+func TestReplaceRRSetRestoresOnJournalFailure(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	dns := api.dns(t)
+	dns.SetJournal(JournalSinkFunc(func(entry *JournalEntry) error {
+		return iif(entry.Operation == OperationCreateRecord, errors.New("disk full"), nil)
+	}), "")
+
+	_, err := dns.GetRecordService().ReplaceRRSet(zone.Id, ptr("www"), ptr("A"), []string{"192.0.2.1", "192.0.2.2"}, nil)
+	assert.ErrorContains(t, err, "journal: disk full")
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "www", "A"), []string{"192.0.2.1"})
+}
+
+func TestDeleteRRSet(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", nil)
+	api.addRecord(*zone.Id, "www", "AAAA", "2001:db8::1", nil)
+
+	err := api.dns(t).GetRecordService().DeleteRRSet(zone.Id, ptr("www"), ptr("A"))
+	assert.NilError(t, err)
+	records := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, *records[0].Type, "AAAA")
+}
+
+func TestRecordChangeInverse(t *testing.T) {
+	before := &Record{Id: ptr("1"), Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")}
+	after := &Record{Id: ptr("1"), Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.2")}
+	update := &RecordChange{Action: ChangeUpdate, Before: before, After: after}
+	assert.Equal(t, update.Inverse().After, before)
+	assert.Equal(t, update.String(), "update www A 192.0.2.1 -> 192.0.2.2")
+	assert.Equal(t, (&RecordChange{Action: ChangeCreate, After: after}).Inverse().Action, ChangeDelete)
+	assert.Assert(t, (&RecordChange{Action: ChangeDelete, Before: before}).Inverse().After.Id == nil)
+}
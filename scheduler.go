@@ -0,0 +1,279 @@
+package gohetznerdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// State of a scheduled change set.
+type ScheduleStatus string
+
+const (
+	SchedulePending ScheduleStatus = "pending"
+	// The change set is being applied. A set found running after a restart was interrupted
+	// and is marked failed, since it may be partially applied.
+	ScheduleRunning   ScheduleStatus = "running"
+	ScheduleApplied   ScheduleStatus = "applied"
+	ScheduleFailed    ScheduleStatus = "failed"
+	ScheduleRefused   ScheduleStatus = "refused"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+// Record changes to apply at a given time. The Before record of updates and deletes is the
+// precondition: it needs the record id, and its name, type, value and TTL, where set, must still
+// match the live record when the set runs, otherwise the whole set is refused. Sets are applied as a
+// [Transaction], so a failing change rolls back the ones before it.
+type ScheduledChangeSet struct {
+	Id          string          `json:"id"`
+	Description string          `json:"description,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	Changes     []*RecordChange `json:"changes"`
+	Status      ScheduleStatus  `json:"status"`
+	Executed    time.Time       `json:"executed,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// Persists scheduled change sets.
+type ScheduleStore interface {
+	Load() ([]*ScheduledChangeSet, error)
+	Save(sets []*ScheduledChangeSet) error
+}
+
+// Keeps scheduled change sets in a single JSON file.
+type FileScheduleStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (store *FileScheduleStore) Load() ([]*ScheduledChangeSet, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sets []*ScheduledChangeSet
+	return sets, json.Unmarshal(data, &sets)
+}
+
+func (store *FileScheduleStore) Save(sets []*ScheduledChangeSet) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	data, err := json.MarshalIndent(sets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(store.Path, data, 0o600)
+}
+
+// Runs scheduled change sets once they are due.
+type Scheduler struct {
+	dns   HetznerDNS
+	store ScheduleStore
+	mu    sync.Mutex
+
+	// Sets due longer than this are refused instead of applied late, zero applies them
+	// whenever the scheduler gets to them.
+	MaxDelay time.Duration
+
+	now func() time.Time
+}
+
+// Creates a scheduler keeping its change sets in store.
+func NewScheduler(dns HetznerDNS, store ScheduleStore) *Scheduler {
+	return &Scheduler{dns: dns.WithoutCache(), store: store, now: time.Now}
+}
+
+// Stores the change set as pending and returns it with its assigned id.
+func (scheduler *Scheduler) Schedule(runAt time.Time, description string, changes ...*RecordChange) (*ScheduledChangeSet, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("schedule: no changes")
+	}
+	for _, change := range changes {
+		if change.Action != ChangeCreate && (change.Before == nil || validateNotEmpty("record_id", change.Before.Id) != nil) {
+			return nil, fmt.Errorf("schedule: %s needs the expected record with its id", change.Action)
+		}
+	}
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	sets, err := scheduler.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	set := &ScheduledChangeSet{Id: newRandomId(), Description: description, RunAt: runAt.UTC(), Changes: changes, Status: SchedulePending}
+	return set, scheduler.store.Save(append(sets, set))
+}
+
+// Cancels a pending change set.
+func (scheduler *Scheduler) Cancel(id string) error {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	sets, err := scheduler.store.Load()
+	if err != nil {
+		return err
+	}
+	for _, set := range sets {
+		if set.Id != id {
+			continue
+		}
+		if set.Status != SchedulePending {
+			return fmt.Errorf("schedule: change set %s is %s", id, set.Status)
+		}
+		set.Status = ScheduleCancelled
+		return scheduler.store.Save(sets)
+	}
+	return fmt.Errorf("schedule: change set %s not found", id)
+}
+
+// Returns all stored change sets ordered by execution time.
+func (scheduler *Scheduler) ChangeSets() ([]*ScheduledChangeSet, error) {
+	sets, err := scheduler.store.Load()
+	sort.SliceStable(sets, func(i, j int) bool { return sets[i].RunAt.Before(sets[j].RunAt) })
+	return sets, err
+}
+
+// Runs the pending change sets that are due and returns them with their outcome. Sets left
+// running by an interrupted run are marked failed and returned as well.
+func (scheduler *Scheduler) RunDue() ([]*ScheduledChangeSet, error) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	sets, err := scheduler.ChangeSets()
+	if err != nil {
+		return nil, err
+	}
+	var done []*ScheduledChangeSet
+	for _, set := range sets {
+		if set.Status == ScheduleRunning {
+			set.Status, set.Error = ScheduleFailed, "interrupted, changes may be partially applied"
+			done = append(done, set)
+		}
+	}
+	if len(done) > 0 {
+		if err := scheduler.store.Save(sets); err != nil {
+			return nil, err
+		}
+	}
+	now := scheduler.now()
+	for _, set := range sets {
+		if set.Status != SchedulePending || now.Before(set.RunAt) {
+			continue
+		}
+		set.Status = ScheduleRunning
+		if err := scheduler.store.Save(sets); err != nil {
+			return done, err
+		}
+		scheduler.run(set, now)
+		set.Executed = scheduler.now().UTC()
+		done = append(done, set)
+		if err := scheduler.store.Save(sets); err != nil {
+			return done, err
+		}
+	}
+	return done, nil
+}
+
+func (scheduler *Scheduler) run(set *ScheduledChangeSet, now time.Time) {
+	if scheduler.MaxDelay > 0 && now.Sub(set.RunAt) > scheduler.MaxDelay {
+		set.Status, set.Error = ScheduleRefused, fmt.Sprintf("missed the execution time by more than %s", scheduler.MaxDelay)
+		return
+	}
+	if err := scheduler.checkPreconditions(set); err != nil {
+		set.Status, set.Error = ScheduleRefused, err.Error()
+		return
+	}
+	tx := NewTransaction(scheduler.dns)
+	for _, change := range set.Changes {
+		switch change.Action {
+		case ChangeCreate:
+			tx.Create(copyRecord(change.After, false))
+		case ChangeUpdate:
+			update := copyRecord(change.After, false)
+			update.Id = change.Before.Id
+			tx.Update(update)
+		case ChangeDelete:
+			tx.Delete(change.Before.Id)
+		}
+	}
+	if _, err := tx.Commit(); err != nil {
+		set.Status, set.Error = ScheduleFailed, err.Error()
+		return
+	}
+	set.Status = ScheduleApplied
+}
+
+func (scheduler *Scheduler) checkPreconditions(set *ScheduledChangeSet) error {
+	for _, change := range set.Changes {
+		if change.Action == ChangeCreate {
+			continue
+		}
+		expected := change.Before
+		current, err := scheduler.dns.GetRecordService().GetRecord(expected.Id)
+		if err != nil {
+			return fmt.Errorf("precondition of %s: %w", change, err)
+		}
+		if (expected.Name != nil && !sameRecordName(expected.Name, current.Name)) ||
+			(expected.Type != nil && !sameRecordType(expected.Type, current.Type)) ||
+			(expected.Value != nil && deref(expected.Value) != deref(current.Value)) ||
+			(expected.TTL != nil && !sameTTL(expected.TTL, current.TTL)) {
+			return fmt.Errorf("precondition of %s: record is now %s %s %s", change, deref(current.Name), deref(current.Type), deref(current.Value))
+		}
+	}
+	return nil
+}
+
+// Interval of [Scheduler.Run] when called with a non-positive interval.
+const defaultScheduleInterval = time.Minute
+
+// Outcome of a change set run by [Scheduler.Run]. ChangeSet is nil when the store failed.
+type ScheduleOutcome struct {
+	ChangeSet *ScheduledChangeSet
+	Err       error
+}
+
+// Checks for due change sets at every interval until the context is done and sends the
+// outcome of each on the returned channel, which is closed when the scheduler stops. A
+// non-positive interval checks every minute.
+func (scheduler *Scheduler) Run(ctx context.Context, interval time.Duration) <-chan *ScheduleOutcome {
+	outcomes := make(chan *ScheduleOutcome)
+	go func() {
+		defer close(outcomes)
+		ticker := time.NewTicker(iif(interval > 0, interval, defaultScheduleInterval))
+		defer ticker.Stop()
+		for {
+			done, err := scheduler.RunDue()
+			var reports []*ScheduleOutcome
+			for _, set := range done {
+				outcome := &ScheduleOutcome{ChangeSet: set}
+				if set.Error != "" {
+					outcome.Err = errors.New(set.Error)
+				}
+				reports = append(reports, outcome)
+			}
+			if err != nil {
+				reports = append(reports, &ScheduleOutcome{Err: err})
+			}
+			for _, report := range reports {
+				select {
+				case outcomes <- report:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return outcomes
+}
@@ -0,0 +1,132 @@
+package gohetznerdns
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	www := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	old := api.addRecord(*zone.Id, "old", "A", "192.0.2.9", nil)
+	dns := api.dns(t)
+	store := &FileScheduleStore{Path: filepath.Join(t.TempDir(), "schedule.json")}
+	now := time.Date(2024, 5, 4, 1, 0, 0, 0, time.UTC)
+	scheduler := NewScheduler(dns, store)
+	scheduler.now = func() time.Time { return now }
+
+	cutover := time.Date(2024, 5, 4, 2, 0, 0, 0, time.UTC)
+	set, err := scheduler.Schedule(cutover, "cutover",
+		&RecordChange{Action: ChangeUpdate, Before: www, After: &Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("198.51.100.1")}},
+		&RecordChange{Action: ChangeDelete, Before: &Record{Id: old.Id, Value: ptr("192.0.2.9")}},
+		&RecordChange{Action: ChangeCreate, After: &Record{ZoneId: zone.Id, Name: ptr("new"), Type: ptr("A"), Value: ptr("198.51.100.2")}},
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, set.Status, SchedulePending)
+
+	done, err := scheduler.RunDue()
+	assert.NilError(t, err)
+	assert.Equal(t, len(done), 0)
+
+	// A new scheduler on the same store picks the set up after a restart.
+	now = cutover.Add(time.Minute)
+	restarted := NewScheduler(dns, store)
+	restarted.now = func() time.Time { return now }
+	done, err = restarted.RunDue()
+	assert.NilError(t, err)
+	assert.Equal(t, len(done), 1)
+	assert.Equal(t, done[0].Id, set.Id)
+	assert.Equal(t, done[0].Status, ScheduleApplied)
+	assert.Equal(t, done[0].Executed, now)
+	records := api.zoneRecords(*zone.Id)
+	assert.DeepEqual(t, recordValues(records, "www", "A"), []string{"198.51.100.1"})
+	assert.DeepEqual(t, recordValues(records, "new", "A"), []string{"198.51.100.2"})
+	assert.Equal(t, len(recordValues(records, "old", "A")), 0)
+
+	sets, err := restarted.ChangeSets()
+	assert.NilError(t, err)
+	assert.Equal(t, sets[0].Status, ScheduleApplied)
+	done, err = restarted.RunDue()
+	assert.NilError(t, err)
+	assert.Equal(t, len(done), 0)
+}
+
+func TestSchedulerRefusesChangedRecords(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	www := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	dns := api.dns(t)
+	scheduler := NewScheduler(dns, &FileScheduleStore{Path: filepath.Join(t.TempDir(), "schedule.json")})
+
+	_, err := scheduler.Schedule(time.Now().Add(-time.Minute), "",
+		&RecordChange{Action: ChangeCreate, After: &Record{ZoneId: zone.Id, Name: ptr("new"), Type: ptr("A"), Value: ptr("198.51.100.2")}},
+		&RecordChange{Action: ChangeUpdate, Before: copyRecord(www, true), After: &Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("198.51.100.1")}},
+	)
+	assert.NilError(t, err)
+	_, err = dns.GetRecordService().UpdateRecord(&Record{Id: www.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.5")})
+	assert.NilError(t, err)
+
+	done, err := scheduler.RunDue()
+	assert.NilError(t, err)
+	assert.Equal(t, done[0].Status, ScheduleRefused)
+	assert.Assert(t, strings.Contains(done[0].Error, "record is now www A 192.0.2.5"), done[0].Error)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 1)
+
+	_, err = scheduler.Schedule(time.Now(), "", &RecordChange{Action: ChangeDelete, Before: &Record{}})
+	assert.ErrorContains(t, err, "needs the expected record")
+}
+
+func TestSchedulerMarksInterruptedSets(t *testing.T) {
+	api := newFakeAPI(t)
+	store := &FileScheduleStore{Path: filepath.Join(t.TempDir(), "schedule.json")}
+	assert.NilError(t, store.Save([]*ScheduledChangeSet{{Id: "a", Status: ScheduleRunning}, {Id: "b", Status: ScheduleCancelled}}))
+
+	done, err := NewScheduler(api.dns(t), store).RunDue()
+	assert.NilError(t, err)
+	assert.Equal(t, len(done), 1)
+	assert.Equal(t, done[0].Status, ScheduleFailed)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	scheduler := NewScheduler(api.dns(t), &FileScheduleStore{Path: filepath.Join(t.TempDir(), "schedule.json")})
+	set, err := scheduler.Schedule(time.Now().Add(20*time.Millisecond), "",
+		&RecordChange{Action: ChangeCreate, After: &Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")}})
+	assert.NilError(t, err)
+	assert.NilError(t, scheduler.Cancel(set.Id))
+	assert.ErrorContains(t, scheduler.Cancel(set.Id), "is cancelled")
+	set, err = scheduler.Schedule(time.Now().Add(20*time.Millisecond), "",
+		&RecordChange{Action: ChangeCreate, After: &Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.2")}})
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outcome := <-scheduler.Run(ctx, 5*time.Millisecond)
+	assert.NilError(t, outcome.Err)
+	assert.Equal(t, outcome.ChangeSet.Id, set.Id)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "www", "A"), []string{"192.0.2.2"})
+}
+
+func TestSchedulerRunWithoutInterval(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	scheduler := NewScheduler(api.dns(t), &FileScheduleStore{Path: filepath.Join(t.TempDir(), "schedule.json")})
+	_, err := scheduler.Schedule(time.Now(), "",
+		&RecordChange{Action: ChangeCreate, After: &Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")}})
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	outcomes := scheduler.Run(ctx, 0)
+	outcome := <-outcomes
+	assert.NilError(t, outcome.Err)
+	cancel()
+	for range outcomes {
+	}
+}
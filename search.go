@@ -0,0 +1,163 @@
+package gohetznerdns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Filters of [SearchRecords]. Unset fields match every record, set fields must all match.
+type SearchQuery struct {
+	// Exact value. Hostnames match regardless of case and trailing dot.
+	Value *string
+	// Glob pattern matched against the value, e.g. "*.herokuapp.com".
+	ValueGlob *string
+	// Network in CIDR notation containing the address of A and AAAA records.
+	Network *string
+	// Glob pattern matched against the relative and the fully qualified record name.
+	Name *string
+	// Regular expression matched against the fully qualified record name without trailing dot.
+	NameRegexp *regexp.Regexp
+	// Record types, e.g. A and AAAA.
+	Types []string
+	// Inclusive TTL range, records without TTL use the zone TTL.
+	MinTTL *int
+	MaxTTL *int
+	// Number of zones fetched in parallel, defaults to 4.
+	Concurrency int
+}
+
+// Record found by [SearchRecords] together with its zone.
+type SearchMatch struct {
+	Zone   *Zone
+	Record *Record
+}
+
+// Returns the fully qualified name of the record without trailing dot.
+func (match *SearchMatch) FQDN() string {
+	return strings.TrimSuffix(qualifyName(deref(match.Record.Name), deref(match.Zone.Name)), ".")
+}
+
+func (match *SearchMatch) String() string {
+	return fmt.Sprintf("%s %s %s", match.FQDN(), deref(match.Record.Type), deref(match.Record.Value))
+}
+
+type searchFilter struct {
+	query   *SearchQuery
+	network *net.IPNet
+}
+
+func newSearchFilter(query *SearchQuery) (*searchFilter, error) {
+	filter := &searchFilter{query: query}
+	for _, pattern := range []*string{query.ValueGlob, query.Name} {
+		if _, err := path.Match(deref(pattern), ""); err != nil {
+			return nil, fmt.Errorf("search: invalid pattern %q", *pattern)
+		}
+	}
+	if query.Network != nil {
+		_, network, err := net.ParseCIDR(*query.Network)
+		if err != nil {
+			return nil, fmt.Errorf("search: invalid network %q", *query.Network)
+		}
+		filter.network = network
+	}
+	return filter, nil
+}
+
+func normalizeSearchValue(value string) string {
+	return strings.TrimSuffix(strings.ToLower(value), ".")
+}
+
+func (filter *searchFilter) matches(zone *Zone, record *Record) bool {
+	query := filter.query
+	match := &SearchMatch{Zone: zone, Record: record}
+	value := deref(record.Value)
+	if len(query.Types) > 0 && !containsFold(query.Types, deref(record.Type)) {
+		return false
+	}
+	if query.Value != nil && value != *query.Value {
+		_, hostname := recordTarget(record)
+		if !hostname || normalizeSearchValue(value) != normalizeSearchValue(*query.Value) {
+			return false
+		}
+	}
+	if query.ValueGlob != nil {
+		if ok, _ := path.Match(normalizeSearchValue(*query.ValueGlob), normalizeSearchValue(value)); !ok {
+			return false
+		}
+	}
+	if filter.network != nil {
+		ip := net.ParseIP(value)
+		if !containsFold([]string{"A", "AAAA"}, deref(record.Type)) || ip == nil || !filter.network.Contains(ip) {
+			return false
+		}
+	}
+	if query.Name != nil {
+		pattern := strings.ToLower(strings.TrimSuffix(*query.Name, "."))
+		relative, _ := path.Match(pattern, normalizeRecordName(deref(record.Name)))
+		qualified, _ := path.Match(pattern, strings.ToLower(match.FQDN()))
+		if !relative && !qualified {
+			return false
+		}
+	}
+	if query.NameRegexp != nil && !query.NameRegexp.MatchString(match.FQDN()) {
+		return false
+	}
+	ttl := deref(iif(record.TTL != nil, record.TTL, zone.TTL))
+	if (query.MinTTL != nil && ttl < *query.MinTTL) || (query.MaxTTL != nil && ttl > *query.MaxTTL) {
+		return false
+	}
+	return true
+}
+
+// Searches the records of all zones of the account. Records of the zones are fetched in
+// parallel, matches are sorted by zone, name and type.
+func SearchRecords(dns HetznerDNS, query *SearchQuery) ([]*SearchMatch, error) {
+	if err := validateNotNil("query", query); err != nil {
+		return nil, err
+	}
+	filter, err := newSearchFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := dns.GetZoneService().GetAllZones()
+	if err != nil {
+		return nil, err
+	}
+	zoneIds := make([]*string, 0, len(zones))
+	for _, zone := range zones {
+		zoneIds = append(zoneIds, zone.Id)
+	}
+	var matches []*SearchMatch
+	var errs []error
+	for i, result := range dns.GetRecordService().GetRecordsForZones(zoneIds, query.Concurrency) {
+		zone := zones[i]
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("zone %s: %w", deref(zone.Name), result.Err))
+			continue
+		}
+		for _, record := range result.Value {
+			if filter.matches(zone, record) {
+				matches = append(matches, &SearchMatch{Zone: zone, Record: record})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if deref(a.Zone.Name) != deref(b.Zone.Name) {
+			return deref(a.Zone.Name) < deref(b.Zone.Name)
+		}
+		if a.FQDN() != b.FQDN() {
+			return a.FQDN() < b.FQDN()
+		}
+		return deref(a.Record.Type) < deref(b.Record.Type)
+	})
+	return matches, nil
+}
@@ -0,0 +1,74 @@
+package gohetznerdns
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestSearchRecords(t *testing.T) {
+	api := newFakeAPI(t)
+	com := api.addZone("example.com", 3600)
+	org := api.addZone("example.org", 86400)
+	api.addRecord(*com.Id, "@", "A", "203.0.113.7", nil)
+	api.addRecord(*com.Id, "www", "A", "203.0.113.8", ptr(300))
+	api.addRecord(*com.Id, "app", "CNAME", "shiny-app.herokuapp.com.", nil)
+	api.addRecord(*com.Id, "@", "TXT", "203.0.113.7", nil)
+	api.addRecord(*org.Id, "api", "A", "203.0.113.7", nil)
+	api.addRecord(*org.Id, "blog", "CNAME", "blog.herokuapp.com", ptr(60))
+	api.addRecord(*org.Id, "v6", "AAAA", "2001:db8::1", nil)
+	dns := api.dns(t)
+
+	search := func(query *SearchQuery) []string {
+		matches, err := SearchRecords(dns, query)
+		assert.NilError(t, err)
+		var got []string
+		for _, match := range matches {
+			got = append(got, match.String())
+		}
+		return got
+	}
+
+	assert.DeepEqual(t, search(&SearchQuery{Value: ptr("203.0.113.7"), Types: []string{"A", "AAAA"}}), []string{
+		"example.com A 203.0.113.7",
+		"api.example.org A 203.0.113.7",
+	})
+	assert.DeepEqual(t, search(&SearchQuery{Network: ptr("203.0.113.0/24")}), []string{
+		"example.com A 203.0.113.7",
+		"www.example.com A 203.0.113.8",
+		"api.example.org A 203.0.113.7",
+	})
+	assert.DeepEqual(t, search(&SearchQuery{Types: []string{"CNAME"}, ValueGlob: ptr("*.herokuapp.com")}), []string{
+		"app.example.com CNAME shiny-app.herokuapp.com.",
+		"blog.example.org CNAME blog.herokuapp.com",
+	})
+	assert.DeepEqual(t, search(&SearchQuery{Name: ptr("a*")}), []string{
+		"app.example.com CNAME shiny-app.herokuapp.com.",
+		"api.example.org A 203.0.113.7",
+	})
+	assert.DeepEqual(t, search(&SearchQuery{NameRegexp: regexp.MustCompile(`^[a-z0-9]+\.example\.org$`), MaxTTL: ptr(3600)}), []string{
+		"blog.example.org CNAME blog.herokuapp.com",
+	})
+	assert.DeepEqual(t, search(&SearchQuery{MinTTL: ptr(86400), Types: []string{"AAAA"}, Concurrency: 1}), []string{
+		"v6.example.org AAAA 2001:db8::1",
+	})
+}
+
+func TestSearchRecordsErrors(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+
+	_, err := SearchRecords(dns, &SearchQuery{Network: ptr("203.0.113.0")})
+	assert.ErrorContains(t, err, "invalid network")
+	_, err = SearchRecords(dns, &SearchQuery{Name: ptr("[")})
+	assert.ErrorContains(t, err, "invalid pattern")
+
+	api.failOn = func(method, path string, body []byte) bool {
+		return strings.HasPrefix(path, "/records")
+	}
+	_, err = SearchRecords(dns, &SearchQuery{})
+	assert.ErrorContains(t, err, "zone "+*zone.Name)
+}
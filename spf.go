@@ -0,0 +1,324 @@
+package gohetznerdns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SPF mechanism qualifier.
+type SPFQualifier string
+
+const (
+	SPFPass     SPFQualifier = "+"
+	SPFFail     SPFQualifier = "-"
+	SPFSoftFail SPFQualifier = "~"
+	SPFNeutral  SPFQualifier = "?"
+)
+
+const spfVersion = "v=spf1"
+
+const spfLookupLimit = 10
+
+var spfMechanismKinds = []string{"all", "include", "a", "mx", "ptr", "ip4", "ip6", "exists"}
+
+var spfLookupKinds = []string{"include", "a", "mx", "ptr", "exists"}
+
+var spfModifierName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+// Single SPF mechanism such as -all, include:_spf.example.com or ip4:192.0.2.0/24.
+// Value holds everything after the colon or slash following the kind, e.g. the
+// domain, address or "/24" for a bare a/24.
+type SPFMechanism struct {
+	Qualifier SPFQualifier
+	Kind      string
+	Value     string
+}
+
+func (mechanism *SPFMechanism) String() string {
+	qualifier := iif(mechanism.Qualifier == SPFPass, "", string(mechanism.Qualifier))
+	switch {
+	case mechanism.Value == "":
+		return qualifier + mechanism.Kind
+	case strings.HasPrefix(mechanism.Value, "/"):
+		return qualifier + mechanism.Kind + mechanism.Value
+	}
+	return qualifier + mechanism.Kind + ":" + mechanism.Value
+}
+
+// Sender Policy Framework policy (RFC 7208).
+type SPF struct {
+	Mechanisms []*SPFMechanism
+	Redirect   string
+	Exp        string
+	// Unknown modifiers as written, e.g. ra=postmaster. RFC 7208 requires them to be ignored.
+	Modifiers []string
+}
+
+// Parses an SPF TXT value, quoted or unquoted.
+func ParseSPF(value string) (*SPF, error) {
+	terms := strings.Fields(unquoteTXT(value))
+	if len(terms) == 0 || !strings.EqualFold(terms[0], spfVersion) {
+		return nil, fmt.Errorf("spf: value does not start with %s", spfVersion)
+	}
+	spf := &SPF{}
+	for _, term := range terms[1:] {
+		lower := strings.ToLower(term)
+		switch {
+		case strings.HasPrefix(lower, "redirect="):
+			spf.Redirect = term[len("redirect="):]
+			continue
+		case strings.HasPrefix(lower, "exp="):
+			spf.Exp = term[len("exp="):]
+			continue
+		}
+		if i := strings.Index(term, "="); i > 0 && spfModifierName.MatchString(term[:i]) {
+			spf.Modifiers = append(spf.Modifiers, term)
+			continue
+		}
+		mechanism := &SPFMechanism{Qualifier: SPFPass}
+		if strings.ContainsAny(term[:1], "+-~?") {
+			mechanism.Qualifier = SPFQualifier(term[:1])
+			term = term[1:]
+		}
+		kind, value := term, ""
+		if i := strings.IndexAny(term, ":/"); i >= 0 {
+			kind, value = term[:i], term[i:]
+			value = strings.TrimPrefix(value, ":")
+		}
+		mechanism.Kind = strings.ToLower(kind)
+		if !containsFold(spfMechanismKinds, mechanism.Kind) {
+			return nil, fmt.Errorf("spf: unknown term %q", term)
+		}
+		mechanism.Value = value
+		spf.Mechanisms = append(spf.Mechanisms, mechanism)
+	}
+	return spf, nil
+}
+
+func (spf *SPF) String() string {
+	terms := []string{spfVersion}
+	for _, mechanism := range spf.Mechanisms {
+		terms = append(terms, mechanism.String())
+	}
+	if spf.Redirect != "" {
+		terms = append(terms, "redirect="+spf.Redirect)
+	}
+	if spf.Exp != "" {
+		terms = append(terms, "exp="+spf.Exp)
+	}
+	terms = append(terms, spf.Modifiers...)
+	return strings.Join(terms, " ")
+}
+
+// Returns the number of DNS lookups the policy needs, not counting those of included policies.
+func (spf *SPF) Lookups() int {
+	count := iif(spf.Redirect != "", 1, 0)
+	for _, mechanism := range spf.Mechanisms {
+		if containsFold(spfLookupKinds, mechanism.Kind) {
+			count++
+		}
+	}
+	return count
+}
+
+// Returns the domains of the include mechanisms.
+func (spf *SPF) Includes() []string {
+	var includes []string
+	for _, mechanism := range spf.Mechanisms {
+		if mechanism.Kind == "include" {
+			includes = append(includes, mechanism.Value)
+		}
+	}
+	return includes
+}
+
+// Validates mechanism arguments, the position of all and the lookup limit.
+func (spf *SPF) Validate() error {
+	for i, mechanism := range spf.Mechanisms {
+		switch mechanism.Kind {
+		case "all":
+			if mechanism.Value != "" {
+				return fmt.Errorf("spf: all does not take a value")
+			}
+			if i != len(spf.Mechanisms)-1 {
+				return fmt.Errorf("spf: all must be the last mechanism")
+			}
+			if spf.Redirect != "" {
+				return fmt.Errorf("spf: redirect is ignored when all is present")
+			}
+		case "include", "exists":
+			if mechanism.Value == "" {
+				return fmt.Errorf("spf: %s requires a domain", mechanism.Kind)
+			}
+		case "ip4", "ip6":
+			if err := validateSPFAddress(mechanism); err != nil {
+				return err
+			}
+		}
+		switch mechanism.Qualifier {
+		case SPFPass, SPFFail, SPFSoftFail, SPFNeutral:
+		default:
+			return fmt.Errorf("spf: invalid qualifier %q", mechanism.Qualifier)
+		}
+	}
+	if lookups := spf.Lookups(); lookups > spfLookupLimit {
+		return fmt.Errorf("spf: %d DNS lookups exceed the limit of %d", lookups, spfLookupLimit)
+	}
+	return nil
+}
+
+func validateSPFAddress(mechanism *SPFMechanism) error {
+	var ip net.IP
+	if strings.Contains(mechanism.Value, "/") {
+		var err error
+		if ip, _, err = net.ParseCIDR(mechanism.Value); err != nil {
+			return fmt.Errorf("spf: invalid %s network %q", mechanism.Kind, mechanism.Value)
+		}
+	} else if ip = net.ParseIP(mechanism.Value); ip == nil {
+		return fmt.Errorf("spf: invalid %s address %q", mechanism.Kind, mechanism.Value)
+	}
+	if (ip.To4() != nil) != (mechanism.Kind == "ip4") {
+		return fmt.Errorf("spf: %s address %q has the wrong family", mechanism.Kind, mechanism.Value)
+	}
+	return nil
+}
+
+// Returns the policy as a TXT record for the given owner name.
+func (spf *SPF) Record(name string) *Record {
+	return &Record{Name: ptr(name), Type: ptr("TXT"), Value: ptr(spf.String())}
+}
+
+// Resolver used to flatten SPF includes. [net.Resolver] implements it.
+type SPFResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// Returns a copy of the policy where include mechanisms are replaced by the ip4 and ip6
+// mechanisms of the included policies, resolving their a and mx mechanisms to addresses.
+// Only passing mechanisms of included policies are kept since an include matches only when
+// the included policy passes; they inherit the qualifier of the include.
+func (spf *SPF) Flatten(resolver SPFResolver) (*SPF, error) {
+	flattened := &SPF{Redirect: spf.Redirect, Exp: spf.Exp, Modifiers: slices.Clone(spf.Modifiers)}
+	seen := map[string]bool{}
+	for _, mechanism := range spf.Mechanisms {
+		if mechanism.Kind != "include" {
+			flattened.Mechanisms = appendSPFMechanism(flattened.Mechanisms, seen, mechanism)
+			continue
+		}
+		addresses, err := resolveSPFAddresses(resolver, mechanism.Value, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			address.Qualifier = mechanism.Qualifier
+			flattened.Mechanisms = appendSPFMechanism(flattened.Mechanisms, seen, address)
+		}
+	}
+	return flattened, nil
+}
+
+func appendSPFMechanism(mechanisms []*SPFMechanism, seen map[string]bool, mechanism *SPFMechanism) []*SPFMechanism {
+	key := mechanism.String()
+	if seen[key] {
+		return mechanisms
+	}
+	seen[key] = true
+	return append(mechanisms, mechanism)
+}
+
+func lookupSPF(resolver SPFResolver, domain string) (*SPF, error) {
+	values, err := resolver.LookupTXT(context.Background(), domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		if strings.HasPrefix(strings.ToLower(value), spfVersion) {
+			return ParseSPF(value)
+		}
+	}
+	return nil, fmt.Errorf("spf: no SPF record found for %s", domain)
+}
+
+// Returns the addresses matched with a pass result by the SPF policy of the domain.
+func resolveSPFAddresses(resolver SPFResolver, domain string, depth int) ([]*SPFMechanism, error) {
+	if depth >= spfLookupLimit {
+		return nil, fmt.Errorf("spf: include depth exceeded at %s", domain)
+	}
+	spf, err := lookupSPF(resolver, domain)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []*SPFMechanism
+	for _, mechanism := range spf.Mechanisms {
+		if mechanism.Qualifier != SPFPass {
+			continue
+		}
+		switch mechanism.Kind {
+		case "ip4", "ip6":
+			addresses = append(addresses, &SPFMechanism{Qualifier: SPFPass, Kind: mechanism.Kind, Value: mechanism.Value})
+		case "include":
+			included, err := resolveSPFAddresses(resolver, mechanism.Value, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, included...)
+		case "a", "mx":
+			resolved, err := resolveSPFHosts(resolver, domain, mechanism)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, resolved...)
+		case "ptr", "exists":
+			return nil, fmt.Errorf("spf: %s in %s cannot be flattened", mechanism.Kind, domain)
+		}
+	}
+	if spf.Redirect != "" {
+		redirected, err := resolveSPFAddresses(resolver, spf.Redirect, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, redirected...)
+	}
+	return addresses, nil
+}
+
+func resolveSPFHosts(resolver SPFResolver, domain string, mechanism *SPFMechanism) ([]*SPFMechanism, error) {
+	target, prefix := domain, ""
+	if mechanism.Value != "" {
+		target, prefix, _ = strings.Cut(mechanism.Value, "/")
+		target = iif(target == "", domain, target)
+	}
+	hosts := []string{target}
+	if mechanism.Kind == "mx" {
+		mxs, err := resolver.LookupMX(context.Background(), target)
+		if err != nil {
+			return nil, err
+		}
+		hosts = nil
+		for _, mx := range mxs {
+			hosts = append(hosts, mx.Host)
+		}
+	}
+	var addresses []*SPFMechanism
+	for _, host := range hosts {
+		ips, err := resolver.LookupIPAddr(context.Background(), host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			kind := iif(ip.IP.To4() != nil, "ip4", "ip6")
+			value := ip.IP.String()
+			if prefix != "" && kind == "ip4" {
+				value += "/" + prefix
+			}
+			addresses = append(addresses, &SPFMechanism{Qualifier: SPFPass, Kind: kind, Value: value})
+		}
+	}
+	return addresses, nil
+}
@@ -0,0 +1,139 @@
+package gohetznerdns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+type stubResolver struct {
+	txt map[string][]string
+	ip  map[string][]string
+	mx  map[string][]string
+}
+
+func (resolver *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if values, ok := resolver.txt[name]; ok {
+		return values, nil
+	}
+	return nil, fmt.Errorf("no such host %s", name)
+}
+
+func (resolver *stubResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	var addresses []net.IPAddr
+	for _, ip := range resolver.ip[host] {
+		addresses = append(addresses, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no such host %s", host)
+	}
+	return addresses, nil
+}
+
+func (resolver *stubResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	var mxs []*net.MX
+	for _, host := range resolver.mx[name] {
+		mxs = append(mxs, &net.MX{Host: host, Pref: 10})
+	}
+	return mxs, nil
+}
+
+func TestParseSPF(t *testing.T) {
+	spf, err := ParseSPF(`"v=spf1 ip4:192.0.2.0/24 a/24 -mx:mail.example.com ~include:_spf.google.com " "redirect=_spf.example.com"`)
+	assert.NilError(t, err)
+	assert.Equal(t, len(spf.Mechanisms), 4)
+	assert.Equal(t, spf.Mechanisms[0].Kind, "ip4")
+	assert.Equal(t, spf.Mechanisms[0].Value, "192.0.2.0/24")
+	assert.Equal(t, spf.Mechanisms[1].Value, "/24")
+	assert.Equal(t, spf.Mechanisms[2].Qualifier, SPFFail)
+	assert.Equal(t, spf.Mechanisms[3].Qualifier, SPFSoftFail)
+	assert.Equal(t, spf.Redirect, "_spf.example.com")
+	assert.DeepEqual(t, spf.Includes(), []string{"_spf.google.com"})
+	assert.Equal(t, spf.Lookups(), 4)
+	assert.Equal(t, spf.String(), "v=spf1 ip4:192.0.2.0/24 a/24 -mx:mail.example.com ~include:_spf.google.com redirect=_spf.example.com")
+	assert.NilError(t, spf.Validate())
+}
+
+func TestParseSPFUnknownModifiers(t *testing.T) {
+	spf, err := ParseSPF("v=spf1 ip4:192.0.2.1 ra=postmaster rp=100 -all")
+	assert.NilError(t, err)
+	assert.Equal(t, len(spf.Mechanisms), 2)
+	assert.DeepEqual(t, spf.Modifiers, []string{"ra=postmaster", "rp=100"})
+	assert.NilError(t, spf.Validate())
+	assert.Equal(t, spf.String(), "v=spf1 ip4:192.0.2.1 -all ra=postmaster rp=100")
+}
+
+func TestParseSPFErrors(t *testing.T) {
+	_, err := ParseSPF("v=DMARC1; p=none")
+	assert.Error(t, err, "spf: value does not start with v=spf1")
+	_, err = ParseSPF("v=spf1 foo:bar -all")
+	assert.Error(t, err, "spf: unknown term \"foo:bar\"")
+}
+
+func TestValidateSPF(t *testing.T) {
+	cases := map[string]string{
+		"v=spf1 -all mx":                     "spf: all must be the last mechanism",
+		"v=spf1 ip4:2001:db8::1 -all":        "spf: ip4 address \"2001:db8::1\" has the wrong family",
+		"v=spf1 ip6:300.1.1.1 -all":          "spf: invalid ip6 address \"300.1.1.1\"",
+		"v=spf1 ip4:192.0.2.0/40 -all":       "spf: invalid ip4 network \"192.0.2.0/40\"",
+		"v=spf1 include -all":                "spf: include requires a domain",
+		"v=spf1 " + strings.Repeat("a ", 11): "spf: 11 DNS lookups exceed the limit of 10",
+	}
+	for value, expected := range cases {
+		spf, err := ParseSPF(value)
+		assert.NilError(t, err)
+		assert.Error(t, spf.Validate(), expected)
+	}
+}
+
+func TestSPFRecord(t *testing.T) {
+	spf := &SPF{Mechanisms: []*SPFMechanism{
+		{Qualifier: SPFPass, Kind: "mx"},
+		{Qualifier: SPFFail, Kind: "all"},
+	}}
+	record := spf.Record("@")
+	assert.Equal(t, *record.Type, "TXT")
+	assert.Equal(t, *record.Value, "v=spf1 mx -all")
+}
+
+func TestFlattenSPF(t *testing.T) {
+	resolver := &stubResolver{
+		txt: map[string][]string{
+			"_spf.provider.net":  {"google-site-verification=x", "v=spf1 ip4:198.51.100.0/24 include:_spf2.provider.net -ip4:198.51.100.7 ~all"},
+			"_spf2.provider.net": {"v=spf1 a mx:mail.provider.net ip6:2001:db8::/32 ?all"},
+		},
+		ip: map[string][]string{
+			"_spf2.provider.net": {"203.0.113.1"},
+			"mx1.provider.net":   {"203.0.113.2", "2001:db8::2"},
+		},
+		mx: map[string][]string{"mail.provider.net": {"mx1.provider.net"}},
+	}
+	spf, _ := ParseSPF("v=spf1 mx include:_spf.provider.net ip4:198.51.100.0/24 -all ra=postmaster")
+	flattened, err := spf.Flatten(resolver)
+	assert.NilError(t, err)
+	assert.Equal(t, flattened.String(), "v=spf1 mx ip4:198.51.100.0/24 ip4:203.0.113.1 ip4:203.0.113.2 ip6:2001:db8::2 ip6:2001:db8::/32 -all ra=postmaster")
+	assert.DeepEqual(t, flattened.Modifiers, []string{"ra=postmaster"})
+	assert.Equal(t, flattened.Lookups(), 1)
+}
+
+func TestFlattenSPFErrors(t *testing.T) {
+	resolver := &stubResolver{txt: map[string][]string{
+		"loop.example.net": {"v=spf1 include:loop.example.net"},
+		"ptr.example.net":  {"v=spf1 ptr"},
+	}}
+	spf, _ := ParseSPF("v=spf1 include:loop.example.net -all")
+	_, err := spf.Flatten(resolver)
+	assert.Error(t, err, "spf: include depth exceeded at loop.example.net")
+
+	spf, _ = ParseSPF("v=spf1 include:ptr.example.net -all")
+	_, err = spf.Flatten(resolver)
+	assert.Error(t, err, "spf: ptr in ptr.example.net cannot be flattened")
+
+	spf, _ = ParseSPF("v=spf1 include:missing.example.net -all")
+	_, err = spf.Flatten(resolver)
+	assert.Error(t, err, "no such host missing.example.net")
+}
@@ -0,0 +1,164 @@
+package gohetznerdns
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TLSA certificate usage field values (RFC 6698, RFC 7218).
+const (
+	TLSAUsagePKIXTA uint8 = 0
+	TLSAUsagePKIXEE uint8 = 1
+	TLSAUsageDANETA uint8 = 2
+	TLSAUsageDANEEE uint8 = 3
+)
+
+// TLSA selector field values.
+const (
+	TLSASelectorCert uint8 = 0
+	TLSASelectorSPKI uint8 = 1
+)
+
+// TLSA matching type field values.
+const (
+	TLSAMatchingFull   uint8 = 0
+	TLSAMatchingSHA256 uint8 = 1
+	TLSAMatchingSHA512 uint8 = 2
+)
+
+// DANE TLSA record data. Data is the hex encoded certificate association data.
+type TLSA struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	Data         string
+}
+
+// Computes the TLSA data for the first certificate or public key in the PEM data. Public keys
+// can only be used with the SPKI selector.
+func NewTLSA(pemData []byte, usage, selector, matchingType uint8) (*TLSA, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("tlsa: no PEM data found")
+	}
+	var data []byte
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("tlsa: %w", err)
+		}
+		data = iif(selector == TLSASelectorCert, cert.Raw, cert.RawSubjectPublicKeyInfo)
+	case "PUBLIC KEY":
+		if selector != TLSASelectorSPKI {
+			return nil, fmt.Errorf("tlsa: a public key requires the SPKI selector")
+		}
+		if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("tlsa: %w", err)
+		}
+		data = block.Bytes
+	default:
+		return nil, fmt.Errorf("tlsa: unsupported PEM block %q", block.Type)
+	}
+	tlsa := &TLSA{Usage: usage, Selector: selector, MatchingType: matchingType}
+	if err := tlsa.validateFields(); err != nil {
+		return nil, err
+	}
+	switch matchingType {
+	case TLSAMatchingSHA256:
+		sum := sha256.Sum256(data)
+		data = sum[:]
+	case TLSAMatchingSHA512:
+		sum := sha512.Sum512(data)
+		data = sum[:]
+	}
+	tlsa.Data = hex.EncodeToString(data)
+	return tlsa, nil
+}
+
+// Parses a TLSA record value such as "3 1 1 <hex>".
+func ParseTLSA(value string) (*TLSA, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("tlsa: expected usage, selector, matching type and data in %q", value)
+	}
+	var numbers [3]uint8
+	for i := range numbers {
+		number, err := strconv.ParseUint(fields[i], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("tlsa: invalid field %q", fields[i])
+		}
+		numbers[i] = uint8(number)
+	}
+	tlsa := &TLSA{Usage: numbers[0], Selector: numbers[1], MatchingType: numbers[2], Data: strings.ToLower(strings.Join(fields[3:], ""))}
+	return tlsa, tlsa.Validate()
+}
+
+func (tlsa *TLSA) validateFields() error {
+	if tlsa.Usage > TLSAUsageDANEEE {
+		return fmt.Errorf("tlsa: invalid usage %d", tlsa.Usage)
+	}
+	if tlsa.Selector > TLSASelectorSPKI {
+		return fmt.Errorf("tlsa: invalid selector %d", tlsa.Selector)
+	}
+	if tlsa.MatchingType > TLSAMatchingSHA512 {
+		return fmt.Errorf("tlsa: invalid matching type %d", tlsa.MatchingType)
+	}
+	return nil
+}
+
+// Validates the fields and that the data length fits the matching type.
+func (tlsa *TLSA) Validate() error {
+	if err := tlsa.validateFields(); err != nil {
+		return err
+	}
+	data, err := hex.DecodeString(tlsa.Data)
+	if err != nil {
+		return fmt.Errorf("tlsa: data is not hex encoded")
+	}
+	expected := map[uint8]int{TLSAMatchingSHA256: sha256.Size, TLSAMatchingSHA512: sha512.Size}[tlsa.MatchingType]
+	if (expected != 0 && len(data) != expected) || len(data) == 0 {
+		return fmt.Errorf("tlsa: data length %d does not match matching type %d", len(data), tlsa.MatchingType)
+	}
+	return nil
+}
+
+func (tlsa *TLSA) String() string {
+	return fmt.Sprintf("%d %d %d %s", tlsa.Usage, tlsa.Selector, tlsa.MatchingType, tlsa.Data)
+}
+
+// Returns the owner name of the TLSA records of a service, e.g. "_25._tcp.mx" for port 25
+// over tcp on the host mx. The host is relative to the zone, "@" denotes the apex.
+func TLSARecordName(port int, protocol string, host string) string {
+	host = normalizeRecordName(host)
+	return fmt.Sprintf("_%d._%s", port, strings.ToLower(protocol)) + iif(host == "@", "", "."+host)
+}
+
+// Returns the TLSA record for the given owner name, see [TLSARecordName].
+func (tlsa *TLSA) Record(name string) *Record {
+	return &Record{Name: ptr(name), Type: ptr("TLSA"), Value: ptr(tlsa.String())}
+}
+
+// Publishes the TLSA records of a service name, replacing any other TLSA records of the name.
+// For a rotation pass the association of the upcoming certificate or key as next so both are
+// published ahead of the renewal; once the new certificate is deployed publish it alone to
+// retire the old association.
+func PublishTLSA(service RecordService, zoneId *string, name string, ttl *int, current *TLSA, next ...*TLSA) (*RRSet, error) {
+	var values []string
+	for i, tlsa := range append([]*TLSA{current}, next...) {
+		if err := validateNotNil(iif(i == 0, "current", "next"), tlsa); err != nil {
+			return nil, err
+		}
+		if err := tlsa.Validate(); err != nil {
+			return nil, err
+		}
+		values = append(values, tlsa.String())
+	}
+	return service.ReplaceRRSet(zoneId, &name, ptr("TLSA"), values, ttl)
+}
@@ -0,0 +1,122 @@
+package gohetznerdns
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func testCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mx.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+	spki, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NilError(t, err)
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki})
+}
+
+func TestNewTLSA(t *testing.T) {
+	cert, certPEM, keyPEM := testCertificate(t)
+	spkiSum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	tlsa, err := NewTLSA(certPEM, TLSAUsageDANEEE, TLSASelectorSPKI, TLSAMatchingSHA256)
+	assert.NilError(t, err)
+	assert.Equal(t, tlsa.String(), "3 1 1 "+hex.EncodeToString(spkiSum[:]))
+
+	fromKey, err := NewTLSA(keyPEM, TLSAUsageDANEEE, TLSASelectorSPKI, TLSAMatchingSHA256)
+	assert.NilError(t, err)
+	assert.Equal(t, fromKey.Data, tlsa.Data)
+
+	full, err := NewTLSA(certPEM, TLSAUsageDANETA, TLSASelectorCert, TLSAMatchingFull)
+	assert.NilError(t, err)
+	assert.Equal(t, full.Data, hex.EncodeToString(cert.Raw))
+
+	sha512, err := NewTLSA(certPEM, TLSAUsageDANEEE, TLSASelectorCert, TLSAMatchingSHA512)
+	assert.NilError(t, err)
+	assert.Equal(t, len(sha512.Data), 128)
+}
+
+func TestNewTLSAErrors(t *testing.T) {
+	_, _, keyPEM := testCertificate(t)
+	_, err := NewTLSA([]byte("garbage"), 3, 1, 1)
+	assert.Error(t, err, "tlsa: no PEM data found")
+	_, err = NewTLSA(keyPEM, 3, TLSASelectorCert, 1)
+	assert.Error(t, err, "tlsa: a public key requires the SPKI selector")
+	_, err = NewTLSA(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}}), 3, 1, 1)
+	assert.Error(t, err, "tlsa: unsupported PEM block \"PRIVATE KEY\"")
+	_, err = NewTLSA(keyPEM, 4, 1, 1)
+	assert.Error(t, err, "tlsa: invalid usage 4")
+}
+
+func TestParseTLSA(t *testing.T) {
+	data := strings.Repeat("AB", 32)
+	tlsa, err := ParseTLSA("3 1 1 " + data[:32] + " " + data[32:])
+	assert.NilError(t, err)
+	assert.Equal(t, tlsa.String(), "3 1 1 "+strings.ToLower(data))
+
+	_, err = ParseTLSA("3 1 1")
+	assert.Error(t, err, "tlsa: expected usage, selector, matching type and data in \"3 1 1\"")
+	_, err = ParseTLSA("3 1 x abcd")
+	assert.Error(t, err, "tlsa: invalid field \"x\"")
+	_, err = ParseTLSA("3 1 1 abcd")
+	assert.Error(t, err, "tlsa: data length 2 does not match matching type 1")
+	_, err = ParseTLSA("3 1 0 zz")
+	assert.Error(t, err, "tlsa: data is not hex encoded")
+}
+
+func TestTLSARecordName(t *testing.T) {
+	assert.Equal(t, TLSARecordName(25, "TCP", "mx"), "_25._tcp.mx")
+	assert.Equal(t, TLSARecordName(443, "tcp", "@"), "_443._tcp")
+	tlsa := &TLSA{Usage: 3, Selector: 1, MatchingType: 0, Data: "abcd"}
+	record := tlsa.Record("_25._tcp.mx")
+	assert.Equal(t, *record.Type, "TLSA")
+	assert.Equal(t, *record.Value, "3 1 0 abcd")
+}
+
+func TestPublishTLSARotation(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	service := api.dns(t).GetRecordService()
+	_, currentPEM, _ := testCertificate(t)
+	_, nextPEM, _ := testCertificate(t)
+	current, _ := NewTLSA(currentPEM, 3, 1, 1)
+	next, _ := NewTLSA(nextPEM, 3, 1, 1)
+	name := TLSARecordName(25, "tcp", "mx")
+
+	rrset, err := PublishTLSA(service, zone.Id, name, nil, current, next)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rrset.Records), 2)
+
+	rrset, err = PublishTLSA(service, zone.Id, name, nil, next)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rrset.Values(), []string{next.String()})
+
+	_, err = PublishTLSA(service, zone.Id, name, nil, &TLSA{Usage: 3, Selector: 1, MatchingType: 1, Data: "ab"})
+	assert.Error(t, err, "tlsa: data length 1 does not match matching type 1")
+
+	_, err = PublishTLSA(service, zone.Id, name, nil, nil)
+	assert.Error(t, err, "900 : current is nil")
+	_, err = PublishTLSA(service, zone.Id, name, nil, next, nil)
+	assert.Error(t, err, "900 : next is nil")
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), name, "TLSA"), []string{next.String()})
+}
@@ -0,0 +1,100 @@
+package gohetznerdns
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Queue of record changes across zones applied in order. When a change fails the changes
+// applied before it are compensated in reverse order to restore the snapshot taken at commit.
+type Transaction struct {
+	dns     HetznerDNS
+	changes []*RecordChange
+}
+
+// Outcome of a committed transaction. On failure Failed is the change that could not be
+// applied, RolledBack the applied changes that were undone and NotRolledBack those whose
+// compensation failed as well.
+type TransactionResult struct {
+	Applied       []*RecordChange
+	Failed        *RecordChange
+	RolledBack    []*RecordChange
+	NotRolledBack []*RecordChange
+}
+
+// Creates an empty transaction.
+func NewTransaction(dns HetznerDNS) *Transaction {
+	return &Transaction{dns: dns.WithoutCache()}
+}
+
+// Queues the creation of a record.
+func (tx *Transaction) Create(record *Record) *Transaction {
+	tx.changes = append(tx.changes, &RecordChange{Action: ChangeCreate, After: record})
+	return tx
+}
+
+// Queues an update of the record with the id of the given record.
+func (tx *Transaction) Update(record *Record) *Transaction {
+	tx.changes = append(tx.changes, &RecordChange{Action: ChangeUpdate, Before: &Record{Id: record.Id}, After: record})
+	return tx
+}
+
+// Queues the deletion of a record.
+func (tx *Transaction) Delete(recordId *string) *Transaction {
+	tx.changes = append(tx.changes, &RecordChange{Action: ChangeDelete, Before: &Record{Id: recordId}})
+	return tx
+}
+
+// Returns the queued changes.
+func (tx *Transaction) Changes() []*RecordChange {
+	return tx.changes
+}
+
+// Snapshots the records touched by updates and deletes, so compensation can restore them.
+func (tx *Transaction) snapshot() error {
+	records := tx.dns.GetRecordService()
+	for _, change := range tx.changes {
+		if change.Action == ChangeCreate {
+			continue
+		}
+		if err := validateNotEmpty("record_id", change.Before.Id); err != nil {
+			return err
+		}
+		before, err := records.GetRecord(change.Before.Id)
+		if err != nil {
+			return fmt.Errorf("snapshot of record %s: %w", *change.Before.Id, err)
+		}
+		change.Before = before
+	}
+	return nil
+}
+
+// Applies the queued changes. Nothing is changed when taking the snapshot fails. The returned
+// error wraps the failure of the change and, if any, the failures of the compensations.
+func (tx *Transaction) Commit() (*TransactionResult, error) {
+	result := &TransactionResult{}
+	if err := tx.snapshot(); err != nil {
+		return result, err
+	}
+	applied, err := applyRecordChanges(tx.dns.GetRecordService(), tx.changes)
+	result.Applied = applied
+	if err == nil {
+		return result, nil
+	}
+	result.Failed = tx.changes[len(applied)]
+	failed, revertErr := revertRecordChanges(tx.dns.GetRecordService(), applied)
+	result.NotRolledBack = failed
+	for _, change := range applied {
+		undone := true
+		for _, other := range failed {
+			undone = undone && other != change
+		}
+		if undone {
+			result.RolledBack = append(result.RolledBack, change)
+		}
+	}
+	if revertErr != nil {
+		return result, errors.Join(err, fmt.Errorf("rollback: %w", revertErr))
+	}
+	return result, err
+}
@@ -0,0 +1,88 @@
+package gohetznerdns
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	other := api.addZone("example.org", 3600)
+	cname := api.addRecord(*zone.Id, "www", "CNAME", "old.example.net.", nil)
+	txt := api.addRecord(*other.Id, "@", "TXT", "obsolete", nil)
+
+	result, err := NewTransaction(api.dns(t)).
+		Create(&Record{ZoneId: zone.Id, Name: ptr("app"), Type: ptr("A"), Value: ptr("192.0.2.1")}).
+		Update(&Record{Id: cname.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("CNAME"), Value: ptr("new.example.net.")}).
+		Delete(txt.Id).
+		Commit()
+	assert.NilError(t, err)
+	assert.Equal(t, len(result.Applied), 3)
+	assert.Assert(t, result.Failed == nil)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 2)
+	assert.Equal(t, len(api.zoneRecords(*other.Id)), 0)
+}
+
+func TestTransactionRollback(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	cname := api.addRecord(*zone.Id, "www", "CNAME", "old.example.net.", ptr(300))
+	txt := api.addRecord(*zone.Id, "@", "TXT", "keep", nil)
+	api.failOn = func(method, path string, body []byte) bool {
+		return method == "DELETE"
+	}
+
+	result, err := NewTransaction(api.dns(t)).
+		Create(&Record{ZoneId: zone.Id, Name: ptr("app"), Type: ptr("A"), Value: ptr("192.0.2.1")}).
+		Update(&Record{Id: cname.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("CNAME"), Value: ptr("new.example.net.")}).
+		Delete(txt.Id).
+		Commit()
+	assert.ErrorContains(t, err, "delete @ TXT keep")
+	assert.Equal(t, result.Failed.Action, ChangeDelete)
+	assert.Equal(t, len(result.Applied), 2)
+	assert.Equal(t, len(result.RolledBack), 1)
+	assert.Equal(t, result.RolledBack[0].Action, ChangeUpdate)
+	assert.Equal(t, len(result.NotRolledBack), 1)
+	assert.Equal(t, result.NotRolledBack[0].Action, ChangeCreate)
+	assert.ErrorContains(t, err, "rollback: delete app A 192.0.2.1")
+
+	records := map[string]*Record{}
+	for _, record := range api.zoneRecords(*zone.Id) {
+		records[*record.Name] = record
+	}
+	assert.Equal(t, *records["www"].Value, "old.example.net.")
+	assert.Equal(t, *records["www"].TTL, 300)
+	assert.Equal(t, *records["@"].Value, "keep")
+}
+
+func TestTransactionFullRollback(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.failOn = func(method, path string, body []byte) bool {
+		return method == "POST" && strings.Contains(string(body), "192.0.2.2")
+	}
+
+	result, err := NewTransaction(api.dns(t)).
+		Create(&Record{ZoneId: zone.Id, Name: ptr("a"), Type: ptr("A"), Value: ptr("192.0.2.1")}).
+		Create(&Record{ZoneId: zone.Id, Name: ptr("b"), Type: ptr("A"), Value: ptr("192.0.2.2")}).
+		Commit()
+	assert.ErrorContains(t, err, "create b A 192.0.2.2")
+	assert.Equal(t, len(result.RolledBack), 1)
+	assert.Equal(t, len(result.NotRolledBack), 0)
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 0)
+}
+
+func TestTransactionSnapshotFailure(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+
+	_, err := NewTransaction(api.dns(t)).
+		Create(&Record{ZoneId: zone.Id, Name: ptr("a"), Type: ptr("A"), Value: ptr("192.0.2.1")}).
+		Delete(ptr("missing")).
+		Commit()
+	assert.Error(t, err, "snapshot of record missing: 404 Not Found")
+	assert.Equal(t, len(api.zoneRecords(*zone.Id)), 0)
+}
@@ -0,0 +1,196 @@
+package gohetznerdns
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Original TTL of a zone lowered by a [TTLCampaign].
+type TTLCampaignZone struct {
+	ZoneId string `json:"zone_id"`
+	Name   string `json:"name"`
+	TTL    *int   `json:"ttl"`
+}
+
+// Original TTL of a record lowered by a [TTLCampaign]. A nil TTL means the record inherited
+// the zone TTL.
+type TTLCampaignRecord struct {
+	RecordId string `json:"record_id"`
+	ZoneId   string `json:"zone_id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	TTL      *int   `json:"ttl"`
+}
+
+// Progress of a TTL campaign as stored in its state file. SafeAfter is the time when every
+// cached answer with an original TTL has expired.
+type TTLCampaignState struct {
+	LoweredTo int                  `json:"lowered_to"`
+	Lowered   time.Time            `json:"lowered,omitempty"`
+	SafeAfter time.Time            `json:"safe_after,omitempty"`
+	Restored  time.Time            `json:"restored,omitempty"`
+	Zones     []*TTLCampaignZone   `json:"zones"`
+	Records   []*TTLCampaignRecord `json:"records"`
+}
+
+// Reports whether all caches have picked up the lowered TTLs.
+func (state *TTLCampaignState) WindowPassed(now time.Time) bool {
+	return !state.Lowered.IsZero() && !now.Before(state.SafeAfter)
+}
+
+// Returns the time left until the window has passed.
+func (state *TTLCampaignState) Remaining(now time.Time) time.Duration {
+	return max(state.SafeAfter.Sub(now), 0)
+}
+
+// Lowers TTLs ahead of a migration and restores them afterwards. The original values are
+// written to the state file before anything is changed, so a restore works across restarts.
+type TTLCampaign struct {
+	dns  HetznerDNS
+	path string
+	now  func() time.Time
+}
+
+// Creates a campaign keeping its state in the file at path.
+func NewTTLCampaign(dns HetznerDNS, path string) *TTLCampaign {
+	return &TTLCampaign{dns: dns.WithoutCache(), path: path, now: time.Now}
+}
+
+// Returns the stored state, or nil when no campaign was started.
+func (campaign *TTLCampaign) Status() (*TTLCampaignState, error) {
+	data, err := os.ReadFile(campaign.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := new(TTLCampaignState)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("ttl campaign: %w", err)
+	}
+	return state, nil
+}
+
+func (campaign *TTLCampaign) save(state *TTLCampaignState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(campaign.path, data, 0o600)
+}
+
+// Lowers the TTL of the records matching the query to ttl. With lowerZones the TTL of their
+// zones is lowered too, covering the records that inherit it; otherwise those records get
+// an explicit TTL. Records and zones already at or below ttl are left alone.
+func (campaign *TTLCampaign) Lower(query *SearchQuery, ttl int, lowerZones bool) (*TTLCampaignState, error) {
+	state, err := campaign.Status()
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Restored.IsZero() {
+		return state, fmt.Errorf("ttl campaign: campaign lowered to %d is not restored yet", state.LoweredTo)
+	}
+	matches, err := SearchRecords(campaign.dns, query)
+	if err != nil {
+		return nil, err
+	}
+	state = &TTLCampaignState{LoweredTo: ttl}
+	zones := map[string]*Zone{}
+	var records []*Record
+	var zoneTTLs []*int
+	for _, match := range matches {
+		zone := match.Zone
+		if lowerZones && zones[*zone.Id] == nil && deref(zone.TTL) > ttl {
+			state.Zones = append(state.Zones, &TTLCampaignZone{ZoneId: *zone.Id, Name: *zone.Name, TTL: zone.TTL})
+			zones[*zone.Id] = zone
+		}
+		effective := iif(match.Record.TTL != nil, match.Record.TTL, zone.TTL)
+		if deref(effective) <= ttl || (lowerZones && match.Record.TTL == nil) {
+			continue
+		}
+		state.Records = append(state.Records, &TTLCampaignRecord{
+			RecordId: *match.Record.Id, ZoneId: *zone.Id, Name: *match.Record.Name, Type: *match.Record.Type, TTL: match.Record.TTL,
+		})
+		records = append(records, match.Record)
+		zoneTTLs = append(zoneTTLs, effective)
+	}
+	if err := campaign.save(state); err != nil {
+		return nil, err
+	}
+
+	longest := 0
+	for _, saved := range state.Zones {
+		if _, err := campaign.dns.GetZoneService().UpdateZone(&saved.ZoneId, &ZoneRequest{Name: &saved.Name, TTL: &ttl}); err != nil {
+			return state, fmt.Errorf("ttl campaign: zone %s: %w", saved.Name, err)
+		}
+		longest = max(longest, deref(saved.TTL))
+	}
+	for i, record := range records {
+		lowered := copyRecord(record, true)
+		lowered.TTL = &ttl
+		if _, err := campaign.dns.GetRecordService().UpdateRecord(lowered); err != nil {
+			return state, fmt.Errorf("ttl campaign: record %s %s: %w", *record.Name, *record.Type, err)
+		}
+		longest = max(longest, deref(zoneTTLs[i]))
+	}
+	state.Lowered = campaign.now().UTC()
+	state.SafeAfter = state.Lowered.Add(time.Duration(longest) * time.Second)
+	return state, campaign.save(state)
+}
+
+// Puts the saved TTLs back. Records keep their current values, only the TTL is restored.
+// Records deleted in the meantime are skipped.
+func (campaign *TTLCampaign) Restore() (*TTLCampaignState, error) {
+	state, err := campaign.Status()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("ttl campaign: no campaign found in %s", campaign.path)
+	}
+	if !state.Restored.IsZero() {
+		return state, nil
+	}
+	records := campaign.dns.GetRecordService()
+	for _, saved := range state.Records {
+		current, err := records.GetRecord(&saved.RecordId)
+		if err != nil {
+			if current, err = campaign.findRecord(saved); err != nil {
+				return state, err
+			}
+			if current == nil {
+				continue
+			}
+		}
+		restored := copyRecord(current, true)
+		restored.TTL = saved.TTL
+		if _, err := records.UpdateRecord(restored); err != nil {
+			return state, fmt.Errorf("ttl campaign: record %s %s: %w", saved.Name, saved.Type, err)
+		}
+	}
+	for _, saved := range state.Zones {
+		if _, err := campaign.dns.GetZoneService().UpdateZone(&saved.ZoneId, &ZoneRequest{Name: &saved.Name, TTL: saved.TTL}); err != nil {
+			return state, fmt.Errorf("ttl campaign: zone %s: %w", saved.Name, err)
+		}
+	}
+	state.Restored = campaign.now().UTC()
+	return state, campaign.save(state)
+}
+
+// Tells a deleted record apart from a failing lookup by listing the records of its zone.
+func (campaign *TTLCampaign) findRecord(saved *TTLCampaignRecord) (*Record, error) {
+	records, err := campaign.dns.GetRecordService().GetAllRecords(&saved.ZoneId)
+	if err != nil {
+		return nil, fmt.Errorf("ttl campaign: record %s %s: %w", saved.Name, saved.Type, err)
+	}
+	for _, record := range records {
+		if deref(record.Id) == saved.RecordId {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,87 @@
+package gohetznerdns
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestTTLCampaign(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 86400)
+	www := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	mail := api.addRecord(*zone.Id, "mail", "A", "192.0.2.2", ptr(3600))
+	api.addRecord(*zone.Id, "short", "A", "192.0.2.3", ptr(60))
+	api.addRecord(*zone.Id, "@", "TXT", "hello", ptr(7200))
+	path := filepath.Join(t.TempDir(), "ttl.json")
+	campaign := NewTTLCampaign(api.dns(t), path)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	campaign.now = func() time.Time { return now }
+
+	state, err := campaign.Lower(&SearchQuery{Types: []string{"A"}}, 300, false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Records), 2)
+	assert.Equal(t, len(state.Zones), 0)
+	assert.Equal(t, state.SafeAfter, now.Add(24*time.Hour))
+	assert.Assert(t, !state.WindowPassed(now.Add(time.Hour)))
+	assert.Equal(t, state.Remaining(now.Add(time.Hour)), 23*time.Hour)
+	assert.Assert(t, state.WindowPassed(now.Add(24*time.Hour)))
+	for _, record := range api.zoneRecords(*zone.Id) {
+		expected := map[string]int{"www": 300, "mail": 300, "short": 60, "@": 7200}[*record.Name]
+		assert.Equal(t, *record.TTL, expected)
+	}
+
+	_, err = campaign.Lower(&SearchQuery{}, 300, false)
+	assert.ErrorContains(t, err, "not restored yet")
+
+	_, err = api.dns(t).GetRecordService().UpdateRecord(&Record{Id: www.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("198.51.100.1"), TTL: ptr(300)})
+	assert.NilError(t, err)
+	state, err = campaign.Restore()
+	assert.NilError(t, err)
+	assert.Equal(t, state.Restored, now)
+	for _, record := range api.zoneRecords(*zone.Id) {
+		switch *record.Name {
+		case "www":
+			assert.Assert(t, record.TTL == nil)
+			assert.Equal(t, *record.Value, "198.51.100.1")
+		case "mail":
+			assert.Equal(t, *record.TTL, 3600)
+			assert.Equal(t, *record.Id, *mail.Id)
+		}
+	}
+}
+
+func TestTTLCampaignZones(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 86400)
+	other := api.addZone("example.org", 86400)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*zone.Id, "mail", "A", "192.0.2.2", ptr(3600))
+	api.addRecord(*other.Id, "www", "AAAA", "2001:db8::1", nil)
+	dns := api.dns(t)
+	path := filepath.Join(t.TempDir(), "ttl.json")
+
+	state, err := NewTTLCampaign(dns, path).Lower(&SearchQuery{Types: []string{"A"}}, 300, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Zones), 1)
+	assert.Equal(t, len(state.Records), 1)
+	lowered, err := dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, *lowered.TTL, 300)
+	assert.Assert(t, api.zoneRecords(*zone.Id)[1].TTL == nil)
+
+	// A new campaign instance picks up the state file.
+	status, err := NewTTLCampaign(dns, path).Status()
+	assert.NilError(t, err)
+	assert.Equal(t, status.LoweredTo, 300)
+	_, err = NewTTLCampaign(dns, path).Restore()
+	assert.NilError(t, err)
+	restored, err := dns.GetZoneService().GetZoneById(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, *restored.TTL, 86400)
+	untouched, err := dns.GetZoneService().GetZoneById(other.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, *untouched.TTL, 86400)
+}
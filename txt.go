@@ -0,0 +1,104 @@
+package gohetznerdns
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Maximum length of a single character string in TXT record data.
+const txtChunkSize = 255
+
+// Splits a TXT value made of one or more quoted strings into their unescaped content. Returns
+// false when the value is not a well formed sequence of quoted strings.
+func splitTXTStrings(value string) ([]string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "\"") {
+		return nil, false
+	}
+	var chunks []string
+	var chunk strings.Builder
+	quoted, escaped := false, false
+	for _, c := range value {
+		switch {
+		case escaped:
+			chunk.WriteRune(c)
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			if quoted {
+				chunks = append(chunks, chunk.String())
+				chunk.Reset()
+			}
+			quoted = !quoted
+		case quoted:
+			chunk.WriteRune(c)
+		case c != ' ' && c != '\t':
+			return nil, false
+		}
+	}
+	if quoted {
+		return nil, false
+	}
+	return chunks, true
+}
+
+// Returns the content of a TXT value, concatenating its quoted strings. Values that are not
+// quoted are returned as they are.
+func unquoteTXT(value string) string {
+	if chunks, ok := splitTXTStrings(value); ok {
+		return strings.Join(chunks, "")
+	}
+	return value
+}
+
+// Quotes TXT content, splitting it into strings of at most 255 bytes and escaping quotes and
+// backslashes. Strings are split between characters, never inside a multi-byte UTF-8 sequence.
+func quoteTXT(content string) string {
+	var chunks []string
+	for len(content) > txtChunkSize {
+		size := txtChunkSize
+		for size > 0 && !utf8.RuneStart(content[size]) {
+			size--
+		}
+		if size == 0 {
+			size = txtChunkSize
+		}
+		chunks = append(chunks, content[:size])
+		content = content[size:]
+	}
+	chunks = append(chunks, content)
+	for i, chunk := range chunks {
+		chunk = strings.ReplaceAll(chunk, `\`, `\\`)
+		chunks[i] = `"` + strings.ReplaceAll(chunk, `"`, `\"`) + `"`
+	}
+	return strings.Join(chunks, " ")
+}
+
+func isTXTRecord(record *Record) bool {
+	return record != nil && record.Value != nil && sameRecordType(record.Type, ptr("TXT"))
+}
+
+// Returns a copy of the record ready to be sent to the API. TXT content that is too long for
+// a single string or contains quotes or backslashes is quoted; already quoted values are
+// sent unchanged.
+func encodeTXTRecord(record *Record) *Record {
+	if !isTXTRecord(record) {
+		return record
+	}
+	value := *record.Value
+	if _, ok := splitTXTStrings(value); ok || (len(value) <= txtChunkSize && !strings.ContainsAny(value, `"\`)) {
+		return record
+	}
+	copy := *record
+	copy.Value = ptr(quoteTXT(value))
+	return &copy
+}
+
+// Replaces quoted TXT values read from the API by their joined content.
+func decodeTXTRecord(record *Record) *Record {
+	if isTXTRecord(record) {
+		record.Value = ptr(unquoteTXT(*record.Value))
+	}
+	return record
+}
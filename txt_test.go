@@ -0,0 +1,87 @@
+package gohetznerdns
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gotest.tools/assert"
+)
+
+func TestSplitTXTStrings(t *testing.T) {
+	chunks, ok := splitTXTStrings(`"v=spf1 " "-all"`)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, chunks, []string{"v=spf1 ", "-all"})
+	_, ok = splitTXTStrings(`"open`)
+	assert.Assert(t, !ok)
+	_, ok = splitTXTStrings(`"a" b "c"`)
+	assert.Assert(t, !ok)
+	_, ok = splitTXTStrings(`plain`)
+	assert.Assert(t, !ok)
+}
+
+func TestUnquoteTXT(t *testing.T) {
+	assert.Equal(t, unquoteTXT("v=spf1 -all"), "v=spf1 -all")
+	assert.Equal(t, unquoteTXT(`"v=spf1 " "-all"`), "v=spf1 -all")
+	assert.Equal(t, unquoteTXT(`"say \"hi\" \\ bye"`), `say "hi" \ bye`)
+	assert.Equal(t, unquoteTXT(`"a" b`), `"a" b`)
+}
+
+func TestQuoteTXT(t *testing.T) {
+	assert.Equal(t, quoteTXT(`say "hi" \ bye`), `"say \"hi\" \\ bye"`)
+	long := strings.Repeat("a", 300) + `"`
+	quoted := quoteTXT(long)
+	chunks, ok := splitTXTStrings(quoted)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(chunks), 2)
+	assert.Equal(t, len(chunks[0]), 255)
+	assert.Equal(t, strings.Join(chunks, ""), long)
+
+	multiByte := strings.Repeat("a", 254) + "€tail"
+	chunks, ok = splitTXTStrings(quoteTXT(multiByte))
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, chunks, []string{strings.Repeat("a", 254), "€tail"})
+	for _, chunk := range chunks {
+		assert.Assert(t, utf8.ValidString(chunk))
+	}
+}
+
+func TestEncodeTXTRecord(t *testing.T) {
+	short := &Record{Type: ptr("TXT"), Value: ptr("v=spf1 -all")}
+	assert.Equal(t, encodeTXTRecord(short), short)
+	quoted := &Record{Type: ptr("TXT"), Value: ptr(`"already" "quoted"`)}
+	assert.Equal(t, encodeTXTRecord(quoted), quoted)
+	other := &Record{Type: ptr("A"), Value: ptr(strings.Repeat("1", 300))}
+	assert.Equal(t, encodeTXTRecord(other), other)
+
+	long := &Record{Type: ptr("TXT"), Value: ptr(strings.Repeat("k", 600))}
+	encoded := encodeTXTRecord(long)
+	assert.Equal(t, len(*long.Value), 600)
+	assert.Equal(t, strings.Count(*encoded.Value, `"`), 6)
+	assert.Equal(t, *decodeTXTRecord(encoded).Value, *long.Value)
+}
+
+func TestRecordServiceChunksLongTXT(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	service := api.dns(t).GetRecordService()
+	key := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA", 8)
+
+	created, err := service.CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("s1._domainkey"), Type: ptr("TXT"), Value: &key})
+	assert.NilError(t, err)
+	assert.Equal(t, *created.Value, key)
+	stored := api.zoneRecords(*zone.Id)[0]
+	assert.Assert(t, strings.HasPrefix(*stored.Value, `"v=DKIM1`))
+	assert.Equal(t, strings.Count(*stored.Value, `" "`), 1)
+
+	record, err := service.GetRecord(created.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, *record.Value, key)
+	records, err := service.GetAllRecords(zone.Id)
+	assert.NilError(t, err)
+	assert.Equal(t, *records[0].Value, key)
+
+	_, result, err := service.EnsureRecord(zone.Id, ptr("s1._domainkey"), ptr("TXT"), &key, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, result, EnsureUnchanged)
+}
@@ -18,6 +18,8 @@ type Zone struct {
 	Paused          *bool     `json:"paused"`
 	Status          *string   `json:"status"`
 	NumberOfRecords *int      `json:"records_count"`
+	Created         *string   `json:"created,omitempty"`
+	Modified        *string   `json:"modified,omitempty"`
 }
 
 type ZoneRequest struct {
@@ -36,3 +36,15 @@ func validateNotEmpty(parameterName string, value *string) error {
 	}
 	return nil
 }
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func deref[T any](value *T) T {
+	var empty T
+	if value == nil {
+		return empty
+	}
+	return *value
+}
@@ -26,3 +26,9 @@ func TestValidateNotEmptyWhenNil(t *testing.T) {
 	var d *string
 	assert.Error(t, validateNotEmpty("test", d), "900 : test is nil")
 }
+
+func TestPtrAndDeref(t *testing.T) {
+	assert.Equal(t, *ptr("data"), "data")
+	assert.Equal(t, deref(ptr(3)), 3)
+	assert.Equal(t, deref[int](nil), 0)
+}
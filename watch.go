@@ -0,0 +1,152 @@
+package gohetznerdns
+
+import (
+	"context"
+	"time"
+)
+
+// Kind of change reported by a [Watcher].
+type WatchEventType string
+
+const (
+	ZoneCreated   WatchEventType = "ZoneCreated"
+	ZoneDeleted   WatchEventType = "ZoneDeleted"
+	RecordCreated WatchEventType = "RecordCreated"
+	RecordUpdated WatchEventType = "RecordUpdated"
+	RecordDeleted WatchEventType = "RecordDeleted"
+	// A poll failed, the state is kept and compared again on the next poll.
+	WatchFailed WatchEventType = "WatchFailed"
+)
+
+// Change observed between two polls. Zone is set for every event except WatchFailed. Record
+// holds the created or updated record or the deleted one, Before the record before an update.
+type WatchEvent struct {
+	Type   WatchEventType
+	Zone   *Zone
+	Record *Record
+	Before *Record
+	Err    error
+}
+
+// Polls zones and records and reports the differences between successive polls. Records of
+// a zone are only fetched when its modified timestamp or record count changed.
+type Watcher struct {
+	dns      HetznerDNS
+	interval time.Duration
+	zones    map[string]*watchedZone
+}
+
+type watchedZone struct {
+	zone    *Zone
+	records []*Record
+}
+
+// Interval of watchers created with a non-positive interval.
+const defaultWatchInterval = time.Minute
+
+// Creates a watcher polling at the given interval, a non-positive interval polls every minute.
+func NewWatcher(dns HetznerDNS, interval time.Duration) *Watcher {
+	return &Watcher{dns: dns.WithoutCache(), interval: iif(interval > 0, interval, defaultWatchInterval)}
+}
+
+// Takes the initial state and then polls until the context is done, sending events on the
+// returned channel. The channel is closed when watching stops. A failing initial poll is
+// reported as WatchFailed and retried at the next interval.
+func (watcher *Watcher) Watch(ctx context.Context) <-chan *WatchEvent {
+	events := make(chan *WatchEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(watcher.interval)
+		defer ticker.Stop()
+		for {
+			for _, event := range watcher.Poll() {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Polls once and returns the changes since the previous poll. The first poll only records the
+// current state. Watch calls it at every interval; calling it directly suits callers with
+// their own schedule.
+func (watcher *Watcher) Poll() []*WatchEvent {
+	zones, err := watcher.dns.GetZoneService().GetAllZones()
+	if err != nil {
+		return []*WatchEvent{{Type: WatchFailed, Err: err}}
+	}
+	initial := watcher.zones == nil
+	current := map[string]*watchedZone{}
+	var events []*WatchEvent
+	for _, zone := range zones {
+		previous, known := watcher.zones[deref(zone.Id)]
+		if known && !zoneModified(previous.zone, zone) {
+			previous.zone = zone
+			current[deref(zone.Id)] = previous
+			continue
+		}
+		records, err := watcher.dns.GetRecordService().GetAllRecords(zone.Id)
+		if err != nil && initial {
+			return []*WatchEvent{{Type: WatchFailed, Zone: zone, Err: err}}
+		}
+		if err != nil {
+			// Keep the previous state so the changes are reported by the next poll.
+			if known {
+				current[deref(zone.Id)] = previous
+			}
+			events = append(events, &WatchEvent{Type: WatchFailed, Zone: zone, Err: err})
+			continue
+		}
+		current[deref(zone.Id)] = &watchedZone{zone: zone, records: records}
+		if initial {
+			continue
+		}
+		var before []*Record
+		if known {
+			before = previous.records
+		} else {
+			events = append(events, &WatchEvent{Type: ZoneCreated, Zone: zone})
+		}
+		events = append(events, recordEvents(zone, compareRecords(before, records))...)
+	}
+	for id, previous := range watcher.zones {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		events = append(events, recordEvents(previous.zone, compareRecords(previous.records, nil))...)
+		events = append(events, &WatchEvent{Type: ZoneDeleted, Zone: previous.zone})
+	}
+	watcher.zones = current
+	return events
+}
+
+func zoneModified(before, after *Zone) bool {
+	if before.Modified == nil || after.Modified == nil {
+		return true
+	}
+	return *before.Modified != *after.Modified || deref(before.NumberOfRecords) != deref(after.NumberOfRecords)
+}
+
+func recordEvents(zone *Zone, changes []*RecordChange) []*WatchEvent {
+	var events []*WatchEvent
+	for _, change := range changes {
+		switch change.Action {
+		case ChangeCreate:
+			events = append(events, &WatchEvent{Type: RecordCreated, Zone: zone, Record: change.After})
+		case ChangeUpdate:
+			events = append(events, &WatchEvent{Type: RecordUpdated, Zone: zone, Record: change.After, Before: change.Before})
+		case ChangeDelete:
+			events = append(events, &WatchEvent{Type: RecordDeleted, Zone: zone, Record: change.Before})
+		}
+	}
+	return events
+}
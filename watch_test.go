@@ -0,0 +1,102 @@
+package gohetznerdns
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	other := api.addZone("example.org", 3600)
+	www := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*other.Id, "@", "TXT", "hello", nil)
+	dns := api.dns(t)
+	watcher := NewWatcher(dns, time.Minute)
+
+	assert.Equal(t, len(watcher.Poll()), 0)
+	assert.Equal(t, len(watcher.Poll()), 0)
+	assert.Equal(t, api.callCount("GET /records"), 2)
+
+	records := dns.GetRecordService()
+	_, err := records.UpdateRecord(&Record{Id: www.Id, ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.2")})
+	assert.NilError(t, err)
+	_, err = records.CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("mail"), Type: ptr("A"), Value: ptr("192.0.2.3")})
+	assert.NilError(t, err)
+	assert.NilError(t, dns.GetZoneService().DeleteZone(other.Id))
+	created, err := dns.GetZoneService().CreateZone(&ZoneRequest{Name: ptr("example.net")})
+	assert.NilError(t, err)
+	_, err = records.CreateRecord(&Record{ZoneId: created.Id, Name: ptr("@"), Type: ptr("A"), Value: ptr("192.0.2.4")})
+	assert.NilError(t, err)
+
+	var got []string
+	for _, event := range watcher.Poll() {
+		got = append(got, string(event.Type)+" "+deref(event.Zone.Name)+" "+deref(iif(event.Record != nil, event.Record, &Record{}).Value))
+	}
+	assert.DeepEqual(t, got, []string{
+		"RecordUpdated example.com 192.0.2.2",
+		"RecordCreated example.com 192.0.2.3",
+		"ZoneCreated example.net ",
+		"RecordCreated example.net 192.0.2.4",
+		"RecordDeleted example.org hello",
+		"ZoneDeleted example.org ",
+	})
+}
+
+func TestWatcherSkipsUnmodifiedZones(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	watcher := NewWatcher(api.dns(t), time.Minute)
+
+	watcher.Poll()
+	watcher.Poll()
+	watcher.Poll()
+	assert.Equal(t, api.callCount("GET /zones"), 3)
+	assert.Equal(t, api.callCount("GET /records"), 1)
+}
+
+func TestWatch(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	dns := api.dns(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := NewWatcher(dns, 10*time.Millisecond).Watch(ctx)
+
+	for api.callCount("GET /records") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	_, err := dns.GetRecordService().CreateRecord(&Record{ZoneId: zone.Id, Name: ptr("www"), Type: ptr("A"), Value: ptr("192.0.2.1")})
+	assert.NilError(t, err)
+	event := <-events
+	assert.Equal(t, event.Type, RecordCreated)
+	assert.Equal(t, *event.Record.Name, "www")
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatcherReportsFailures(t *testing.T) {
+	api := newFakeAPI(t)
+	api.addZone("example.com", 3600)
+	api.failOn = func(method, path string, body []byte) bool {
+		return strings.HasPrefix(path, "/zones")
+	}
+	events := NewWatcher(api.dns(t), time.Minute).Poll()
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Type, WatchFailed)
+	assert.Assert(t, events[0].Err != nil)
+}
+
+func TestWatcherDefaultInterval(t *testing.T) {
+	dns, err := NewClient("token")
+	assert.NilError(t, err)
+	assert.Equal(t, NewWatcher(dns, 0).interval, defaultWatchInterval)
+	assert.Equal(t, NewWatcher(dns, -time.Second).interval, defaultWatchInterval)
+}
@@ -34,10 +34,32 @@ type ZoneService interface {
 
 	// Import a zone file. [https://dns.hetzner.com/api-docs#operation/ImportZoneFilePlain]
 	ImportZoneFile(zoneId, zoneFile *string) (*Zone, error)
+
+	// Creates a new zone and copies all records except NS and SOA of the source zone into it.
+	// CNAME, MX and SRV targets inside the source domain are rewritten to the new domain.
+	CloneZone(srcZoneId, newName *string) (*Zone, error)
+
+	// Clones the zone to the new name and deletes the source zone once the copy is verified.
+	RenameZone(srcZoneId, newName *string) (*Zone, error)
+
+	// Imports a zone file by applying only the record changes needed to match it, so unchanged
+	// records keep their ids. SOA and apex NS records are managed by Hetzner and left untouched.
+	ImportZoneFileIncremental(zoneId, zoneFile *string) (*ChangeSummary, error)
 }
 
 type zoneService struct {
 	client *client
+	// Reads skip the cache, see [HetznerDNS.WithoutCache].
+	bypassCache bool
+}
+
+func (service *zoneService) recordService() RecordService {
+	return &recordService{client: service.client, bypassCache: service.bypassCache}
+}
+
+// Returns the service reading past the cache, used by reads that writes are based on.
+func (service *zoneService) fresh() *zoneService {
+	return &zoneService{client: service.client, bypassCache: true}
 }
 
 func (service *zoneService) GetAllZones() ([]*Zone, error) {
@@ -63,6 +85,7 @@ func (service *zoneService) GetAllZonesByName(name *string) ([]*Zone, error) {
 			createJsonRequest(200).
 			setQueryParams(params).
 			setResult(zoneList).
+			setBypassCache(service.bypassCache).
 			execute("GET", zonesBasePath)
 		if err != nil {
 			return nil, err
@@ -84,6 +107,7 @@ func (service *zoneService) GetZoneById(zoneId *string) (*Zone, error) {
 	_, err := service.client.
 		createJsonRequest(200).
 		setResult(zone).
+		setBypassCache(service.bypassCache).
 		execute("GET", zonesBasePath+"/"+*zoneId)
 	if err != nil {
 		return nil, err
@@ -107,15 +131,32 @@ func (service *zoneService) CreateZone(request *ZoneRequest) (*Zone, error) {
 	if zone.Error != nil {
 		return nil, zone.Error.Error()
 	}
-	return zone.Zone, nil
+	return zone.Zone, service.client.writeJournal(&JournalEntry{Operation: OperationCreateZone, ZoneAfter: zone.Zone})
+}
+
+// Returns the zone and optionally its records for the journal, nil when journaling is off.
+func (service *zoneService) journalSnapshot(zoneId *string, withRecords bool) (*Zone, []*Record, error) {
+	if !service.client.journaling() {
+		return nil, nil, nil
+	}
+	zone, err := service.fresh().GetZoneById(zoneId)
+	if err != nil || !withRecords {
+		return zone, nil, err
+	}
+	records, err := service.fresh().recordService().GetAllRecords(zoneId)
+	return zone, records, err
 }
 
 func (service *zoneService) UpdateZone(zoneId *string, request *ZoneRequest) (*Zone, error) {
 	if err := validateNotEmpty("zoneId", zoneId); err != nil {
 		return nil, err
 	}
+	before, _, err := service.journalSnapshot(zoneId, false)
+	if err != nil {
+		return nil, err
+	}
 	zone := new(ZoneResponse)
-	_, err := service.client.
+	_, err = service.client.
 		createJsonRequest(200).
 		setResult(zone).
 		setBody(request).
@@ -126,7 +167,7 @@ func (service *zoneService) UpdateZone(zoneId *string, request *ZoneRequest) (*Z
 	if zone.Error != nil {
 		return nil, zone.Error.Error()
 	}
-	return zone.Zone, nil
+	return zone.Zone, service.client.writeJournal(&JournalEntry{Operation: OperationUpdateZone, ZoneBefore: before, ZoneAfter: zone.Zone})
 }
 
 func (service *zoneService) DeleteZone(zoneId *string) error {
@@ -134,10 +175,18 @@ func (service *zoneService) DeleteZone(zoneId *string) error {
 		return err
 	}
 
-	_, err := service.client.
+	// A zone that is not found is already gone, the delete is a no-op then.
+	before, records, err := service.journalSnapshot(zoneId, true)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	_, err = service.client.
 		createJsonRequest(200, 404).
 		execute("DELETE", zonesBasePath+"/"+*zoneId)
-	return err
+	if err != nil || before == nil {
+		return err
+	}
+	return service.client.writeJournal(&JournalEntry{Operation: OperationDeleteZone, ZoneBefore: before, RecordsBefore: records})
 }
 
 func (service *zoneService) ValidateZoneFile(zoneFile *string) error {
@@ -169,6 +218,7 @@ func (service *zoneService) ExportZoneFile(zoneId *string) (*string, error) {
 
 	zone, err := service.client.
 		createTextRequest(200).
+		setBypassCache(service.bypassCache).
 		execute("GET", zonesBasePath+"/"+*zoneId+"/export")
 	if err != nil {
 		return nil, err
@@ -185,8 +235,12 @@ func (service *zoneService) ImportZoneFile(zoneId, zoneFile *string) (*Zone, err
 		return nil, err
 	}
 
+	before, records, err := service.journalSnapshot(zoneId, true)
+	if err != nil {
+		return nil, err
+	}
 	zone := new(ZoneResponse)
-	_, err := service.client.
+	_, err = service.client.
 		createTextRequest(200).
 		setResult(zone).
 		setBody(*zoneFile).
@@ -198,5 +252,5 @@ func (service *zoneService) ImportZoneFile(zoneId, zoneFile *string) (*Zone, err
 	if zone.Error != nil {
 		return nil, zone.Error.Error()
 	}
-	return zone.Zone, err
+	return zone.Zone, service.client.writeJournal(&JournalEntry{Operation: OperationImportZoneFile, ZoneBefore: before, ZoneAfter: zone.Zone, RecordsBefore: records})
 }
@@ -0,0 +1,116 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"strings"
+)
+
+var cloneRewrittenRecordTypes = []string{"CNAME", "MX", "NS", "SRV"}
+
+// Rewrites an absolute target inside the old domain to the new domain. The target is the last
+// field of the value, so MX priorities and SRV priority, weight and port are preserved.
+func rewriteRecordTarget(recordType, value, oldDomain, newDomain string) string {
+	if !containsFold(cloneRewrittenRecordTypes, recordType) {
+		return value
+	}
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return value
+	}
+	target := fields[len(fields)-1]
+	old := strings.ToLower(strings.TrimSuffix(oldDomain, ".")) + "."
+	lower := strings.ToLower(target)
+	switch {
+	case lower == old:
+		target = strings.TrimSuffix(newDomain, ".") + "."
+	case strings.HasSuffix(lower, "."+old):
+		target = target[:len(target)-len(old)] + strings.TrimSuffix(newDomain, ".") + "."
+	default:
+		return value
+	}
+	fields[len(fields)-1] = target
+	return strings.Join(fields, " ")
+}
+
+func (service *zoneService) cloneRecords(source *Zone, newName *string) ([]*Record, error) {
+	records, err := service.fresh().recordService().GetAllRecords(source.Id)
+	if err != nil {
+		return nil, err
+	}
+	var cloned []*Record
+	for _, record := range records {
+		// SOA and apex NS are created by the API, delegations of subdomains are copied.
+		if isManagedRecord(record) {
+			continue
+		}
+		value := rewriteRecordTarget(deref(record.Type), deref(record.Value), deref(source.Name), *newName)
+		cloned = append(cloned, &Record{Name: record.Name, Type: record.Type, Value: &value, TTL: record.TTL})
+	}
+	return cloned, nil
+}
+
+func (service *zoneService) CloneZone(srcZoneId, newName *string) (*Zone, error) {
+	if err := validateNotEmpty("newName", newName); err != nil {
+		return nil, err
+	}
+	source, err := service.GetZoneById(srcZoneId)
+	if err != nil {
+		return nil, err
+	}
+	records, err := service.cloneRecords(source, newName)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := service.CreateZone(&ZoneRequest{Name: newName, TTL: source.TTL})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		record.ZoneId = zone.Id
+		if _, err := service.recordService().CreateRecord(record); err != nil {
+			return zone, err
+		}
+	}
+	return zone, nil
+}
+
+func (service *zoneService) RenameZone(srcZoneId, newName *string) (*Zone, error) {
+	zone, err := service.CloneZone(srcZoneId, newName)
+	if err != nil {
+		return zone, err
+	}
+	if err := service.verifyClone(srcZoneId, zone); err != nil {
+		return zone, err
+	}
+	return zone, service.DeleteZone(srcZoneId)
+}
+
+// Checks that every record expected from the source zone exists in the cloned zone.
+func (service *zoneService) verifyClone(srcZoneId *string, zone *Zone) error {
+	service = service.fresh()
+	source, err := service.GetZoneById(srcZoneId)
+	if err != nil {
+		return err
+	}
+	expected, err := service.cloneRecords(source, zone.Name)
+	if err != nil {
+		return err
+	}
+	actual, err := service.recordService().GetAllRecords(zone.Id)
+	if err != nil {
+		return err
+	}
+	for _, record := range expected {
+		found := false
+		for _, candidate := range filterRecords(actual, record.Name, record.Type) {
+			if sameRecordValue(record.Type, candidate.Value, record.Value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("clone verification failed: %s %s %s missing in %s", deref(record.Name), deref(record.Type), deref(record.Value), deref(zone.Name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestRewriteRecordTarget(t *testing.T) {
+	assert.Equal(t, rewriteRecordTarget("CNAME", "www.old.com.", "old.com", "new.org"), "www.new.org.")
+	assert.Equal(t, rewriteRecordTarget("CNAME", "OLD.com.", "old.com", "new.org"), "new.org.")
+	assert.Equal(t, rewriteRecordTarget("MX", "10 mail.old.com.", "old.com", "new.org"), "10 mail.new.org.")
+	assert.Equal(t, rewriteRecordTarget("SRV", "0 5 5060 sip.old.com.", "old.com", "new.org"), "0 5 5060 sip.new.org.")
+	assert.Equal(t, rewriteRecordTarget("CNAME", "cdn.example.net.", "old.com", "new.org"), "cdn.example.net.")
+	assert.Equal(t, rewriteRecordTarget("CNAME", "www.bold.com.", "old.com", "new.org"), "www.bold.com.")
+	assert.Equal(t, rewriteRecordTarget("CNAME", "www", "old.com", "new.org"), "www")
+	assert.Equal(t, rewriteRecordTarget("TXT", "old.com.", "old.com", "new.org"), "old.com.")
+}
+
+func TestCloneZone(t *testing.T) {
+	api := newFakeAPI(t)
+	source := api.addZone("old.com", 600)
+	api.addRecord(*source.Id, "@", "NS", "hydrogen.ns.hetzner.com.", nil)
+	api.addRecord(*source.Id, "@", "SOA", "hydrogen.ns.hetzner.com. dns.hetzner.com. 1 86400 10800 3600000 3600", nil)
+	api.addRecord(*source.Id, "@", "A", "192.0.2.1", ptr(300))
+	api.addRecord(*source.Id, "www", "CNAME", "old.com.", nil)
+	api.addRecord(*source.Id, "@", "MX", "10 mail.old.com.", nil)
+
+	zone, err := api.dns(t).GetZoneService().CloneZone(source.Id, ptr("new.org"))
+	assert.NilError(t, err)
+	assert.Equal(t, *zone.Name, "new.org")
+	assert.Equal(t, *zone.TTL, 600)
+	records := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(records), 3)
+	assert.Equal(t, *records[0].Value, "192.0.2.1")
+	assert.Equal(t, *records[0].TTL, 300)
+	assert.Equal(t, *records[1].Value, "10 mail.new.org.")
+	assert.Equal(t, *records[2].Value, "new.org.")
+	assert.Equal(t, len(api.zoneRecords(*source.Id)), 5)
+}
+
+func TestCloneZoneExisting(t *testing.T) {
+	api := newFakeAPI(t)
+	source := api.addZone("old.com", 600)
+	api.addZone("new.org", 600)
+
+	_, err := api.dns(t).GetZoneService().CloneZone(source.Id, ptr("new.org"))
+	assert.Error(t, err, "422 Unprocessable Entity")
+}
+
+func TestRenameZone(t *testing.T) {
+	api := newFakeAPI(t)
+	source := api.addZone("old.com", 600)
+	api.addRecord(*source.Id, "@", "NS", "hydrogen.ns.hetzner.com.", nil)
+	api.addRecord(*source.Id, "www", "A", "192.0.2.1", nil)
+	api.addRecord(*source.Id, "sub", "NS", "ns1.other.net.", nil)
+	api.addRecord(*source.Id, "lab", "NS", "ns.lab.old.com.", nil)
+
+	zone, err := api.dns(t).GetZoneService().RenameZone(source.Id, ptr("new.org"))
+	assert.NilError(t, err)
+	records := api.zoneRecords(*zone.Id)
+	assert.Equal(t, len(records), 3)
+	assert.DeepEqual(t, recordValues(records, "sub", "NS"), []string{"ns1.other.net."})
+	assert.DeepEqual(t, recordValues(records, "lab", "NS"), []string{"ns.lab.new.org."})
+	assert.Assert(t, api.zones[*source.Id] == nil)
+}
+
+func TestRenameZoneKeepsSourceOnFailedCopy(t *testing.T) {
+	api := newFakeAPI(t)
+	source := api.addZone("old.com", 600)
+	api.addRecord(*source.Id, "www", "A", "192.0.2.1", nil)
+	api.failOn = func(method, path string, body []byte) bool {
+		return method == "POST" && path == "/records"
+	}
+
+	_, err := api.dns(t).GetZoneService().RenameZone(source.Id, ptr("new.org"))
+	assert.Error(t, err, "500 Internal Server Error")
+	assert.Assert(t, api.zones[*source.Id] != nil)
+}
@@ -0,0 +1,140 @@
+package gohetznerdns
+
+import "sort"
+
+// Summary of the record changes applied to a zone.
+type ChangeSummary struct {
+	Created   int
+	Updated   int
+	Deleted   int
+	Unchanged int
+	Changes   []*RecordChange
+}
+
+func newChangeSummary(changes []*RecordChange) *ChangeSummary {
+	summary := &ChangeSummary{Changes: changes}
+	for _, change := range changes {
+		switch change.Action {
+		case ChangeCreate:
+			summary.Created++
+		case ChangeUpdate:
+			summary.Updated++
+		case ChangeDelete:
+			summary.Deleted++
+		}
+	}
+	return summary
+}
+
+// Summarizes the changes applied to make a zone match the desired records. Desired records
+// that were neither created nor updated count as unchanged, managed records are not counted.
+func newSyncSummary(changes []*RecordChange, desired []*Record) *ChangeSummary {
+	summary := newChangeSummary(changes)
+	for _, record := range desired {
+		summary.Unchanged += iif(isManagedRecord(record), 0, 1)
+	}
+	summary.Unchanged -= summary.Created + summary.Updated
+	return summary
+}
+
+// Reports whether the record is maintained by Hetzner: the SOA record and the apex NS records.
+func isManagedRecord(record *Record) bool {
+	return sameRecordType(record.Type, ptr("SOA")) ||
+		(sameRecordType(record.Type, ptr("NS")) && sameRecordName(record.Name, ptr("@")))
+}
+
+type rrsetKey struct {
+	name       string
+	recordType string
+}
+
+func groupRRSets(records []*Record) (map[rrsetKey][]*Record, []rrsetKey) {
+	groups := map[rrsetKey][]*Record{}
+	var keys []rrsetKey
+	for _, record := range records {
+		key := rrsetKey{name: normalizeRecordName(deref(record.Name)), recordType: normalizeRecordType(deref(record.Type))}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], record)
+	}
+	return groups, keys
+}
+
+// Computes the changes that turn the current records of a zone into the desired ones. Changes
+// are ordered creates, updates, deletes across all RRsets, managed records are ignored.
+func planZoneChanges(zoneId *string, current, desired []*Record) []*RecordChange {
+	var unmanagedCurrent, unmanagedDesired []*Record
+	for _, record := range current {
+		if !isManagedRecord(record) {
+			unmanagedCurrent = append(unmanagedCurrent, record)
+		}
+	}
+	for _, record := range desired {
+		if !isManagedRecord(record) {
+			unmanagedDesired = append(unmanagedDesired, record)
+		}
+	}
+	currentSets, currentKeys := groupRRSets(unmanagedCurrent)
+	desiredSets, desiredKeys := groupRRSets(unmanagedDesired)
+	keys := desiredKeys
+	for _, key := range currentKeys {
+		if _, ok := desiredSets[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name || (keys[i].name == keys[j].name && keys[i].recordType < keys[j].recordType)
+	})
+
+	var creates, updates, deletes []*RecordChange
+	for _, key := range keys {
+		var values []string
+		var ttl *int
+		name, recordType := key.name, key.recordType
+		for _, record := range desiredSets[key] {
+			values = append(values, deref(record.Value))
+			ttl = iif(ttl == nil, record.TTL, ttl)
+			name = deref(record.Name)
+		}
+		for _, change := range planRRSetChanges(zoneId, &name, &recordType, currentSets[key], values, ttl) {
+			switch change.Action {
+			case ChangeCreate:
+				creates = append(creates, change)
+			case ChangeUpdate:
+				updates = append(updates, change)
+			case ChangeDelete:
+				deletes = append(deletes, change)
+			}
+		}
+	}
+	return append(append(creates, updates...), deletes...)
+}
+
+func (service *zoneService) ImportZoneFileIncremental(zoneId, zoneFile *string) (*ChangeSummary, error) {
+	if err := validateNotEmpty("zoneFile", zoneFile); err != nil {
+		return nil, err
+	}
+	service = service.fresh()
+	zone, err := service.GetZoneById(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := ParseZoneFile(deref(zone.Name), *zoneFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range desired {
+		// Records inheriting the zone TTL are read back without one.
+		if sameTTL(record.TTL, zone.TTL) {
+			record.TTL = nil
+		}
+	}
+	current, err := service.recordService().GetAllRecords(zoneId)
+	if err != nil {
+		return nil, err
+	}
+	changes := planZoneChanges(zoneId, current, desired)
+	applied, err := applyRecordChanges(service.recordService(), changes)
+	return newSyncSummary(applied, desired), err
+}
@@ -0,0 +1,95 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestImportZoneFileIncremental(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	ns := api.addRecord(*zone.Id, "@", "NS", "hydrogen.ns.hetzner.com.", nil)
+	www := api.addRecord(*zone.Id, "www", "A", "192.0.2.1", ptr(300))
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.2", ptr(300))
+	mail := api.addRecord(*zone.Id, "mail", "A", "192.0.2.10", ptr(300))
+	api.addRecord(*zone.Id, "old", "TXT", "obsolete", nil)
+	zoneFile := `$ORIGIN example.com.
+@ IN SOA hydrogen.ns.hetzner.com. dns.hetzner.com. 1 86400 10800 3600000 3600
+www 300 IN A 192.0.2.1
+mail 300 IN A 192.0.2.11
+ftp 300 IN CNAME www
+`
+
+	summary, err := api.dns(t).GetZoneService().ImportZoneFileIncremental(zone.Id, &zoneFile)
+	assert.NilError(t, err)
+	assert.Equal(t, summary.Created, 1)
+	assert.Equal(t, summary.Updated, 1)
+	assert.Equal(t, summary.Deleted, 2)
+	assert.Equal(t, summary.Unchanged, 1)
+
+	records := map[string]*Record{}
+	for _, record := range api.zoneRecords(*zone.Id) {
+		records[*record.Name+" "+*record.Type] = record
+	}
+	assert.Equal(t, len(records), 4)
+	assert.Equal(t, *records["www A"].Id, *www.Id)
+	assert.Equal(t, *records["mail A"].Id, *mail.Id)
+	assert.Equal(t, *records["mail A"].Value, "192.0.2.11")
+	assert.Equal(t, *records["@ NS"].Id, *ns.Id)
+	assert.Equal(t, *records["ftp CNAME"].Value, "www")
+}
+
+func TestImportZoneFileIncrementalNoChanges(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", ptr(300))
+	zoneFile := "www.example.com. 300 IN A 192.0.2.1\n"
+
+	summary, err := api.dns(t).GetZoneService().ImportZoneFileIncremental(zone.Id, &zoneFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(summary.Changes), 0)
+	assert.Equal(t, summary.Unchanged, 1)
+}
+
+func TestImportZoneFileIncrementalIdempotent(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	zoneFile := `www 300 IN A 192.0.2.1
+@ 300 IN TXT "v=spf1 -all"
+quote 300 IN TXT "say \"hi\""
+`
+	zones := api.dns(t).GetZoneService()
+
+	summary, err := zones.ImportZoneFileIncremental(zone.Id, &zoneFile)
+	assert.NilError(t, err)
+	assert.Equal(t, summary.Created, 3)
+	assert.DeepEqual(t, recordValues(api.zoneRecords(*zone.Id), "@", "TXT"), []string{"v=spf1 -all"})
+
+	summary, err = zones.ImportZoneFileIncremental(zone.Id, &zoneFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(summary.Changes), 0)
+	assert.Equal(t, summary.Unchanged, 3)
+}
+
+func TestImportZoneFileIncrementalZoneTTL(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 86400)
+	api.addRecord(*zone.Id, "www", "A", "192.0.2.1", nil)
+	zoneFile := "$TTL 86400\nwww IN A 192.0.2.1\n"
+
+	summary, err := api.dns(t).GetZoneService().ImportZoneFileIncremental(zone.Id, &zoneFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(summary.Changes), 0)
+	assert.Equal(t, summary.Unchanged, 1)
+	assert.Assert(t, api.zoneRecords(*zone.Id)[0].TTL == nil)
+}
+
+func TestImportZoneFileIncrementalInvalid(t *testing.T) {
+	api := newFakeAPI(t)
+	zone := api.addZone("example.com", 3600)
+	zoneFile := "www 300 IN\n"
+
+	_, err := api.dns(t).GetZoneService().ImportZoneFileIncremental(zone.Id, &zoneFile)
+	assert.Error(t, err, "line 1: record www without type or data")
+}
@@ -0,0 +1,219 @@
+package gohetznerdns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var recordClasses = []string{"IN", "CH", "HS", "CS"}
+
+// Parses a zone file in RFC 1035 master file format into records. Names are returned relative
+// to the origin, "@" denotes the apex. Record values are the record data fields joined by a
+// single space, quoted strings are kept as written, except for TXT values, which are returned as
+// their joined content like records read from the API. The TTL is only set when the record or a
+// preceding $TTL directive specifies one.
+func ParseZoneFile(origin string, zoneFile string) ([]*Record, error) {
+	parser := &zoneFileParser{origin: normalizeOrigin(origin)}
+	lines, err := joinZoneFileLines(zoneFile)
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, line := range lines {
+		record, err := parser.parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+		if record != nil {
+			records = append(records, decodeTXTRecord(record))
+		}
+	}
+	return records, nil
+}
+
+type zoneFileLine struct {
+	number   int
+	indented bool
+	tokens   []string
+}
+
+type zoneFileParser struct {
+	origin     string
+	defaultTTL *int
+	lastOwner  string
+}
+
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), ".")) + "."
+}
+
+// Splits the zone file into logical lines, removing comments and joining parenthesised
+// continuations. Quoted strings are kept as single tokens including their quotes.
+func joinZoneFileLines(zoneFile string) ([]*zoneFileLine, error) {
+	var lines []*zoneFileLine
+	var current *zoneFileLine
+	depth := 0
+	for number, text := range strings.Split(zoneFile, "\n") {
+		if current == nil {
+			current = &zoneFileLine{number: number + 1, indented: len(text) > 0 && unicode.IsSpace(rune(text[0]))}
+		}
+		token := strings.Builder{}
+		inToken, quoted, escaped := false, false, false
+		flush := func() {
+			if inToken {
+				current.tokens = append(current.tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		}
+	chars:
+		for _, c := range text {
+			switch {
+			case escaped:
+				token.WriteRune(c)
+				escaped = false
+			case c == '\\':
+				token.WriteRune(c)
+				escaped, inToken = true, true
+			case c == '"':
+				token.WriteRune(c)
+				quoted, inToken = !quoted, true
+			case quoted:
+				token.WriteRune(c)
+			case c == ';':
+				break chars
+			case c == '(':
+				flush()
+				depth++
+			case c == ')':
+				flush()
+				if depth == 0 {
+					return nil, fmt.Errorf("line %d: unbalanced parenthesis", number+1)
+				}
+				depth--
+			case unicode.IsSpace(c):
+				flush()
+			default:
+				token.WriteRune(c)
+				inToken = true
+			}
+		}
+		if quoted {
+			return nil, fmt.Errorf("line %d: unterminated quoted string", number+1)
+		}
+		flush()
+		if depth == 0 {
+			if len(current.tokens) > 0 {
+				lines = append(lines, current)
+			}
+			current = nil
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parenthesis", current.number)
+	}
+	return lines, nil
+}
+
+// Parses a TTL given in seconds or with s, m, h, d and w unit suffixes such as 1h30m.
+func parseTTL(value string) (int, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds, nil
+	}
+	units := map[rune]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, number := 0, ""
+	for _, c := range strings.ToLower(value) {
+		if unicode.IsDigit(c) {
+			number += string(c)
+			continue
+		}
+		unit, ok := units[c]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("invalid ttl %q", value)
+		}
+		n, _ := strconv.Atoi(number)
+		total += n * unit
+		number = ""
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid ttl %q", value)
+	}
+	return total, nil
+}
+
+func isTTL(value string) bool {
+	if len(value) == 0 || !unicode.IsDigit(rune(value[0])) {
+		return false
+	}
+	_, err := parseTTL(value)
+	return err == nil
+}
+
+// Returns the owner name relative to the origin.
+func (parser *zoneFileParser) relativeName(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case name == "@" || lower == parser.origin:
+		return "@"
+	case strings.HasSuffix(lower, "."+parser.origin):
+		return name[:len(name)-len(parser.origin)-1]
+	}
+	return name
+}
+
+func (parser *zoneFileParser) parseLine(line *zoneFileLine) (*Record, error) {
+	tokens := line.tokens
+	switch strings.ToUpper(tokens[0]) {
+	case "$ORIGIN":
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("$ORIGIN without domain")
+		}
+		parser.origin = normalizeOrigin(tokens[1])
+		return nil, nil
+	case "$TTL":
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("$TTL without value")
+		}
+		ttl, err := parseTTL(tokens[1])
+		if err != nil {
+			return nil, err
+		}
+		parser.defaultTTL = &ttl
+		return nil, nil
+	case "$INCLUDE", "$GENERATE":
+		return nil, fmt.Errorf("%s is not supported", tokens[0])
+	}
+
+	owner := parser.lastOwner
+	if !line.indented {
+		owner = parser.relativeName(tokens[0])
+		tokens = tokens[1:]
+	}
+	if owner == "" {
+		return nil, fmt.Errorf("record without owner name")
+	}
+	parser.lastOwner = owner
+
+	ttl := parser.defaultTTL
+	for len(tokens) > 0 {
+		if isTTL(tokens[0]) {
+			value, _ := parseTTL(tokens[0])
+			ttl = &value
+		} else if containsFold(recordClasses, tokens[0]) {
+			if !strings.EqualFold(tokens[0], "IN") {
+				return nil, fmt.Errorf("class %s is not supported", tokens[0])
+			}
+		} else {
+			break
+		}
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 2 {
+		return nil, fmt.Errorf("record %s without type or data", owner)
+	}
+	recordType := strings.ToUpper(tokens[0])
+	value := strings.Join(tokens[1:], " ")
+	return &Record{Name: &owner, Type: &recordType, Value: &value, TTL: ttl}, nil
+}
@@ -0,0 +1,67 @@
+package gohetznerdns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseZoneFile(t *testing.T) {
+	zoneFile := `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.example.com. hostmaster.example.com. (
+		2024010101 ; serial
+		7200 3600 1209600 3600 )
+@		86400	IN	NS	hydrogen.ns.hetzner.com.
+www.example.com.	300	A	192.0.2.1
+	IN	A	192.0.2.2 ; second address
+mail	IN	300	MX	10 mail.example.net.
+txt	TXT	"v=spf1 include:_spf.example.net; -all" "second"
+`
+	records, err := ParseZoneFile("example.com", zoneFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 6)
+	assert.Equal(t, *records[0].Type, "SOA")
+	assert.Equal(t, *records[0].Value, "ns1.example.com. hostmaster.example.com. 2024010101 7200 3600 1209600 3600")
+	assert.Equal(t, *records[0].TTL, 3600)
+	assert.Equal(t, *records[1].TTL, 86400)
+	assert.Equal(t, *records[2].Name, "www")
+	assert.Equal(t, *records[2].TTL, 300)
+	assert.Equal(t, *records[3].Name, "www")
+	assert.Equal(t, *records[3].Value, "192.0.2.2")
+	assert.Equal(t, *records[3].TTL, 3600)
+	assert.Equal(t, *records[4].Value, "10 mail.example.net.")
+	assert.Equal(t, *records[4].TTL, 300)
+	assert.Equal(t, *records[5].Value, "v=spf1 include:_spf.example.net; -allsecond")
+}
+
+func TestParseZoneFileWithoutTTL(t *testing.T) {
+	records, err := ParseZoneFile("example.com.", "@ A 192.0.2.1\n")
+	assert.NilError(t, err)
+	assert.Equal(t, *records[0].Name, "@")
+	assert.Assert(t, records[0].TTL == nil)
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	_, err := ParseZoneFile("example.com", "@ SOA ( a b\n")
+	assert.Error(t, err, "line 1: unbalanced parenthesis")
+	_, err = ParseZoneFile("example.com", "@ TXT \"open\n")
+	assert.Error(t, err, "line 1: unterminated quoted string")
+	_, err = ParseZoneFile("example.com", "@ 300\n")
+	assert.Error(t, err, "line 1: record @ without type or data")
+	_, err = ParseZoneFile("example.com", "\n\tA 192.0.2.1\n")
+	assert.Error(t, err, "line 2: record without owner name")
+	_, err = ParseZoneFile("example.com", "$TTL 1x\n")
+	assert.Error(t, err, "line 1: invalid ttl \"1x\"")
+}
+
+func TestParseTTL(t *testing.T) {
+	ttl, err := parseTTL("1h30m")
+	assert.NilError(t, err)
+	assert.Equal(t, ttl, 5400)
+	ttl, err = parseTTL("1W")
+	assert.NilError(t, err)
+	assert.Equal(t, ttl, 604800)
+	_, err = parseTTL("h")
+	assert.Error(t, err, "invalid ttl \"h\"")
+}